@@ -0,0 +1,193 @@
+package loom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	echoContextType = reflect.TypeOf((*echo.Context)(nil)).Elem()
+	stdContextType  = reflect.TypeOf((*context.Context)(nil)).Elem()
+	componentType   = reflect.TypeOf((*templ.Component)(nil)).Elem()
+	errorType       = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// newMethodCall checks the signature of a controller action once at registration time.
+// Supported actions take an echo.Context or a context.Context, optionally followed by a
+// params struct (or a pointer to one) which is bound from the path params, query and form
+// and validated before the call. They return an error, or a templ.Component and an error
+// in which case Loom renders the component with Loom.Renderer:
+//
+//	func (c echo.Context) error
+//	func (c echo.Context, p ShowParams) error
+//	func (ctx context.Context, in CreateInput) (templ.Component, error)
+func newMethodCall(instance any, method reflect.Method) *methodCall {
+	mc := &methodCall{
+		controllerInstance: instance,
+		method:             method,
+	}
+
+	// In(0) is the receiver
+	t := method.Type
+
+	invalid := func() {
+		panic(fmt.Sprintf("Method %s on controller type %s has unsupported action signature %s",
+			method.Name, reflect.TypeOf(instance), t))
+	}
+
+	if t.NumIn() < 2 || t.NumIn() > 3 {
+		invalid()
+	}
+
+	switch t.In(1) {
+	case echoContextType:
+	case stdContextType:
+		mc.stdContext = true
+	default:
+		invalid()
+	}
+
+	if t.NumIn() == 3 {
+		params := t.In(2)
+
+		structType := params
+		if structType.Kind() == reflect.Pointer {
+			structType = structType.Elem()
+		}
+
+		if structType.Kind() != reflect.Struct {
+			invalid()
+		}
+
+		mc.params = params
+	}
+
+	switch {
+	case t.NumOut() == 1 && t.Out(0) == errorType:
+	case t.NumOut() == 2 && t.Out(0) == componentType && t.Out(1) == errorType:
+		mc.renders = true
+	default:
+		invalid()
+	}
+
+	return mc
+}
+
+// call binds the action arguments from the request, calls the action on the controller
+// instance and renders the returned component if there is one
+func (l *Loom) call(mc *methodCall, instance any, c echo.Context) error {
+	args := make([]reflect.Value, 2, 3)
+
+	args[0] = reflect.ValueOf(instance)
+
+	if mc.stdContext {
+		args[1] = reflect.ValueOf(c.Request().Context())
+	} else {
+		args[1] = reflect.ValueOf(c)
+	}
+
+	if mc.params != nil {
+		params, err := l.bind(c, mc.params)
+		if err != nil {
+			return err
+		}
+
+		args = append(args, params)
+	}
+
+	results := mc.method.Func.Call(args)
+
+	if err, _ := results[len(results)-1].Interface().(error); err != nil {
+		return err
+	}
+
+	if !mc.renders {
+		return nil
+	}
+
+	component, _ := results[0].Interface().(templ.Component)
+	if component == nil {
+		return nil
+	}
+
+	return l.Renderer(c, component)
+}
+
+// bind decodes the path params, query and form values into a new value of type t
+// (a struct or a pointer to one) and validates it. Path params take precedence.
+func (l *Loom) bind(c echo.Context, t reflect.Type) (reflect.Value, error) {
+	req := c.Request()
+
+	if err := req.ParseForm(); err != nil {
+		return reflect.Value{}, NewHTTPError(http.StatusBadRequest, err.Error()).Wrap(err)
+	}
+
+	values := make(url.Values, len(req.Form))
+	for key, value := range req.Form {
+		values[key] = value
+	}
+
+	paramValues := c.ParamValues()
+	for i, name := range c.ParamNames() {
+		if i < len(paramValues) {
+			values.Set(name, paramValues[i])
+		}
+	}
+
+	structType := t
+	if t.Kind() == reflect.Pointer {
+		structType = t.Elem()
+	}
+
+	ptr := reflect.New(structType)
+
+	if err := l.formDecoder.Decode(ptr.Interface(), values); err != nil {
+		return reflect.Value{}, NewHTTPError(http.StatusBadRequest, err.Error()).Wrap(err)
+	}
+
+	if err := l.validator.Struct(ptr.Interface()); err != nil {
+		var validationErrors validator.ValidationErrors
+		if !errors.As(err, &validationErrors) {
+			return reflect.Value{}, err
+		}
+
+		return reflect.Value{}, NewHTTPError(http.StatusUnprocessableEntity, validationMessage(validationErrors)).Wrap(err)
+	}
+
+	if t.Kind() == reflect.Pointer {
+		return ptr, nil
+	}
+
+	return ptr.Elem(), nil
+}
+
+func validationMessage(errors validator.ValidationErrors) string {
+	messages := make([]string, 0, len(errors))
+
+	for _, err := range errors {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// newValidator creates the validator shared by Loom and controllers,
+// fields are reported by their form names
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	v.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		return strings.SplitN(fld.Tag.Get("form"), ",", 2)[0]
+	})
+
+	return v
+}
@@ -0,0 +1,156 @@
+package loom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/labstack/echo/v4"
+)
+
+type showParams struct {
+	ID   int    `form:"id" validate:"required"`
+	Sort string `form:"sort"`
+}
+
+type createInput struct {
+	Name  string `form:"name" validate:"required"`
+	Email string `form:"email" validate:"required,email"`
+}
+
+type typedController struct {
+	Controller
+}
+
+func (tc *typedController) Show(c echo.Context, p showParams) error {
+	return c.String(http.StatusOK, fmt.Sprintf("%d %s", p.ID, p.Sort))
+}
+
+func (tc *typedController) Edit(c echo.Context, p *showParams) error {
+	return c.String(http.StatusOK, fmt.Sprintf("edit %d", p.ID))
+}
+
+func (tc *typedController) Create(ctx context.Context, in createInput) (templ.Component, error) {
+	return templ.Raw("created " + in.Name + " " + in.Email), nil
+}
+
+func (tc *typedController) Home(ctx context.Context) (templ.Component, error) {
+	return templ.Raw("home"), nil
+}
+
+func (tc *typedController) Fail(ctx context.Context) (templ.Component, error) {
+	return nil, errors.New("failed")
+}
+
+func (tc *typedController) Helper(n int) error {
+	return nil
+}
+
+func (tc *typedController) Scalar(c echo.Context, id int) error {
+	return nil
+}
+
+func (tc *typedController) NoError(c echo.Context) string {
+	return ""
+}
+
+func newTypedLoom() *Loom {
+	loom := New(NewDeps())
+
+	Register[*typedController](loom)
+
+	return loom
+}
+
+func TestAction_BindParams(t *testing.T) {
+	loom := newTypedLoom()
+
+	loom.GET("/items/:id", "typed.show")
+	loom.GET("/items/:id/edit", "typed.edit")
+
+	rec := serve(loom, http.MethodGet, "/items/42?sort=name&id=7")
+	if rec.Code != http.StatusOK || rec.Body.String() != "42 name" {
+		t.Errorf("response = %d %v, want 200 42 name", rec.Code, rec.Body.String())
+	}
+
+	rec = serve(loom, http.MethodGet, "/items/5/edit")
+	if rec.Body.String() != "edit 5" {
+		t.Errorf("body = %v, want edit 5", rec.Body.String())
+	}
+
+	rec = serve(loom, http.MethodGet, "/items/abc")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %v, want %v", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestAction_RenderComponent(t *testing.T) {
+	loom := newTypedLoom()
+
+	loom.POST("/items", "typed.create")
+	loom.GET("/", "typed.home")
+	loom.GET("/fail", "typed.fail")
+
+	loom.Renderer = func(c echo.Context, component templ.Component, opts ...RenderOption) error {
+		return testLayout("layout")(component).Render(c.Request().Context(), c.Response().Writer)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader("name=Jane&email=jane@example.com"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec := httptest.NewRecorder()
+	loom.E.ServeHTTP(rec, req)
+
+	if rec.Body.String() != "layout:created Jane jane@example.com" {
+		t.Errorf("body = %v, want layout:created Jane jane@example.com", rec.Body.String())
+	}
+
+	rec = serve(loom, http.MethodGet, "/")
+	if rec.Body.String() != "layout:home" {
+		t.Errorf("body = %v, want layout:home", rec.Body.String())
+	}
+
+	rec = serve(loom, http.MethodGet, "/fail")
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %v, want %v", rec.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestAction_ValidationFailure(t *testing.T) {
+	loom := newTypedLoom()
+
+	loom.POST("/items", "typed.create")
+
+	req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader("name=Jane&email=nope"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec := httptest.NewRecorder()
+	loom.E.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %v, want %v", rec.Code, http.StatusUnprocessableEntity)
+	}
+
+	if !strings.Contains(rec.Body.String(), "email") {
+		t.Errorf("body = %v, want it to mention the email field", rec.Body.String())
+	}
+}
+
+func TestAction_UnsupportedSignature(t *testing.T) {
+	for _, action := range []string{"typed.helper", "typed.scalar", "typed.no_error"} {
+		t.Run(action, func(t *testing.T) {
+			loom := newTypedLoom()
+
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("GET() expected panic for unsupported signature, got none")
+				}
+			}()
+
+			loom.GET("/", action)
+		})
+	}
+}
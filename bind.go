@@ -0,0 +1,41 @@
+package loom
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ActionFunc is a reflection-free binding of a controller action, usually a method expression:
+// (*ContactsController).Index
+type ActionFunc[T any] func(ctrl T, c echo.Context) error
+
+// Bind registers reflection-free bindings for actions of a registered controller, keyed by method name.
+// Loom prefers them over calling the action through reflection. Bindings are generated by
+// `loom gen controllers` and have to be registered before the routes using them.
+// Usage:
+//
+//	loom.Bind(l, map[string]loom.ActionFunc[*ContactsController]{
+//		"Index": (*ContactsController).Index,
+//	})
+func Bind[T any](l *Loom, actions map[string]ActionFunc[T]) {
+	var zero T
+
+	typeName := controllerKey(reflect.TypeOf(zero))
+
+	controller, exists := l.controllerRegistry[typeName]
+	if !exists {
+		panic(fmt.Sprintf("Controller type %s not found in registry", typeName))
+	}
+
+	if controller.bindings == nil {
+		controller.bindings = make(map[string]func(ctrl any, c echo.Context) error)
+	}
+
+	for methodName, action := range actions {
+		controller.bindings[methodName] = func(ctrl any, c echo.Context) error {
+			return action(ctrl.(T), c)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package loom
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestBind(t *testing.T) {
+	loom := New(NewDeps())
+
+	Register[*testController](loom)
+
+	bound := false
+
+	Bind(loom, map[string]ActionFunc[*testController]{
+		"Index": func(ctrl *testController, c echo.Context) error {
+			bound = true
+			return ctrl.Index(c)
+		},
+	})
+
+	loom.GET("/", "test.index")
+	loom.GET("/show", "test.show")
+
+	rec := serve(loom, http.MethodGet, "/")
+	if !bound {
+		t.Error("binding was not used")
+	}
+	if strings.TrimSpace(rec.Body.String()) != "index" {
+		t.Errorf("body = %v, want index", rec.Body.String())
+	}
+
+	// actions without a binding fall back to reflection
+	rec = serve(loom, http.MethodGet, "/show")
+	if strings.TrimSpace(rec.Body.String()) != "show" {
+		t.Errorf("body = %v, want show", rec.Body.String())
+	}
+}
+
+func TestBind_ControllerNotFound(t *testing.T) {
+	loom := New(NewDeps())
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Bind() expected panic for non-existent controller, got none")
+		}
+	}()
+
+	Bind(loom, map[string]ActionFunc[*testController]{
+		"Index": (*testController).Index,
+	})
+}
+
+func benchmarkHandler(b *testing.B, bind bool) {
+	loom := New(NewDeps())
+
+	Register[*testController](loom)
+
+	if bind {
+		Bind(loom, map[string]ActionFunc[*testController]{
+			"Index": (*testController).Index,
+		})
+	}
+
+	handler := loom.handlerFor("test.index")
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c := loom.E.NewContext(req, httptest.NewRecorder())
+
+		if err := handler(c); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkHandler_Reflection(b *testing.B) {
+	benchmarkHandler(b, false)
+}
+
+func BenchmarkHandler_Binding(b *testing.B) {
+	benchmarkHandler(b, true)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/aneshas/loom"
+)
+
+// configPath is the directory holding the application config files
+const configPath = "./config"
+
+// runConfigShowCommand prints the effective config of the current environment
+func runConfigShowCommand() error {
+	settings, err := loom.ExplainConfig[cfg](configPath, loom.Env())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("# %s config\n", loom.Env())
+
+	return loom.WriteConfigSettings(os.Stdout, settings)
+}
+
+// runConfigCheckCommand validates the config files of every environment
+func runConfigCheckCommand() error {
+	envs, err := loom.ConfigEnvs(configPath)
+	if err != nil {
+		return err
+	}
+
+	if len(envs) == 0 {
+		return fmt.Errorf("no environment config files found in %s", configPath)
+	}
+
+	failed := 0
+
+	for _, env := range envs {
+		if err := loom.CheckConfig[cfg](configPath, env); err != nil {
+			fmt.Printf("✗ %s: %v\n", env, err)
+			failed++
+
+			continue
+		}
+
+		fmt.Printf("✓ %s\n", env)
+	}
+
+	if failed > 0 {
+		return errors.New("invalid config")
+	}
+
+	return nil
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const controllersGenFile = "controller_gen.go"
+
+// scopedDirective marks a controller type registered with loom.RegisterScoped:
+//
+//	//loom:scoped
+//	type SessionsController struct { ... }
+const scopedDirective = "//loom:scoped"
+
+// filterMethods have the action signature but are run as filters, not routed
+var filterMethods = []string{"BeforeAction", "AfterAction"}
+
+// boundController is a controller type found in web/controller with its bindable actions
+type boundController struct {
+	Name    string
+	Actions []string
+
+	// Scoped controllers get a fresh instance for every request
+	Scoped bool
+}
+
+// runGenControllersCommand scans web/controller and generates controller_gen.go with the
+// Register function, binding every func(echo.Context) error action with a method expression
+// so Loom does not have to call them through reflection. Controllers marked with //loom:scoped
+// are registered with loom.RegisterScoped
+func runGenControllersCommand() error {
+	dir := filepath.Join("web", "controller")
+
+	controllers, err := scanControllers(dir)
+	if err != nil {
+		return err
+	}
+
+	src, err := generateControllersFile(filepath.Base(dir), controllers)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, controllersGenFile), src, 0o644); err != nil {
+		return fmt.Errorf("failed to write controllers file: %w", err)
+	}
+
+	return nil
+}
+
+// scanControllers parses the controller package and returns its *Controller types sorted by name
+func scanControllers(dir string) ([]boundController, error) {
+	fset := token.NewFileSet()
+
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != controllersGenFile
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", dir, err)
+	}
+
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected a single package in %s, found %d", dir, len(pkgs))
+	}
+
+	types := make(map[string]*boundController)
+
+	for _, pkg := range pkgs {
+		for filename, file := range pkg.Files {
+			echoName := importName(file, "github.com/labstack/echo/v4", "echo")
+
+			for _, decl := range file.Decls {
+				switch decl := decl.(type) {
+				case *ast.GenDecl:
+					for _, spec := range decl.Specs {
+						ts, ok := spec.(*ast.TypeSpec)
+						if !ok || !strings.HasSuffix(ts.Name.Name, "Controller") {
+							continue
+						}
+
+						if _, ok := ts.Type.(*ast.StructType); !ok {
+							continue
+						}
+
+						if _, ok := types[ts.Name.Name]; !ok {
+							types[ts.Name.Name] = &boundController{Name: ts.Name.Name}
+						}
+
+						// the doc of a lone type declaration is attached to the declaration
+						types[ts.Name.Name].Scoped = hasDirective(ts.Doc, scopedDirective) ||
+							len(decl.Specs) == 1 && hasDirective(decl.Doc, scopedDirective)
+					}
+
+				case *ast.FuncDecl:
+					if decl.Recv == nil {
+						if decl.Name.Name == "Register" {
+							return nil, fmt.Errorf("%s declares Register, remove it since it is generated now", filename)
+						}
+
+						continue
+					}
+
+					if !decl.Name.IsExported() || slices.Contains(filterMethods, decl.Name.Name) || !isActionSignature(decl.Type, echoName) {
+						continue
+					}
+
+					recv := receiverName(decl.Recv.List[0].Type)
+
+					if _, ok := types[recv]; !ok {
+						types[recv] = &boundController{Name: recv}
+					}
+
+					types[recv].Actions = append(types[recv].Actions, decl.Name.Name)
+				}
+			}
+		}
+	}
+
+	var controllers []boundController
+
+	for _, ctrl := range types {
+		if !strings.HasSuffix(ctrl.Name, "Controller") {
+			continue
+		}
+
+		slices.Sort(ctrl.Actions)
+		controllers = append(controllers, *ctrl)
+	}
+
+	slices.SortFunc(controllers, func(a, b boundController) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return controllers, nil
+}
+
+// isActionSignature reports whether a method has the plain func(echo.Context) error signature
+func isActionSignature(ft *ast.FuncType, echoName string) bool {
+	if ft.Params.NumFields() != 1 || ft.Results.NumFields() != 1 {
+		return false
+	}
+
+	sel, ok := ft.Params.List[0].Type.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Context" {
+		return false
+	}
+
+	if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != echoName {
+		return false
+	}
+
+	result, ok := ft.Results.List[0].Type.(*ast.Ident)
+
+	return ok && result.Name == "error"
+}
+
+// hasDirective reports whether the comment group contains the directive line
+func hasDirective(doc *ast.CommentGroup, directive string) bool {
+	if doc == nil {
+		return false
+	}
+
+	for _, c := range doc.List {
+		if strings.TrimSpace(c.Text) == directive {
+			return true
+		}
+	}
+
+	return false
+}
+
+// receiverName returns the type name of a method receiver: *ContactsController -> ContactsController
+func receiverName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+
+	return ""
+}
+
+// importName returns the name the file refers to an imported package by
+func importName(file *ast.File, path, defaultName string) string {
+	for _, imp := range file.Imports {
+		if p, _ := strconv.Unquote(imp.Path.Value); p != path {
+			continue
+		}
+
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+
+		return defaultName
+	}
+
+	return ""
+}
+
+func generateControllersFile(pkgName string, controllers []boundController) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString("// Code generated by loom gen controllers. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkgName)
+	buf.WriteString("import \"github.com/aneshas/loom\"\n\n")
+
+	buf.WriteString("// Register registers the controllers with reflection-free bindings for their actions\n")
+	buf.WriteString("func Register(l *loom.Loom) {\n")
+
+	for i, ctrl := range controllers {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+
+		register := "Register"
+		if ctrl.Scoped {
+			register = "RegisterScoped"
+		}
+
+		fmt.Fprintf(&buf, "\tloom.%s[*%s](l)\n", register, ctrl.Name)
+
+		if len(ctrl.Actions) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&buf, "\tloom.Bind(l, map[string]loom.ActionFunc[*%s]{\n", ctrl.Name)
+
+		for _, action := range ctrl.Actions {
+			fmt.Fprintf(&buf, "\t\t%q: (*%s).%s,\n", action, ctrl.Name, action)
+		}
+
+		buf.WriteString("\t})\n")
+	}
+
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format controllers file: %w", err)
+	}
+
+	return src, nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeControllerFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := filepath.Join(t.TempDir(), "controller")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestScanControllers(t *testing.T) {
+	dir := writeControllerFiles(t, map[string]string{
+		"contacts.go": `package controller
+
+import (
+	"context"
+
+	"github.com/aneshas/loom"
+	"github.com/labstack/echo/v4"
+)
+
+type ContactsController struct {
+	loom.Controller
+}
+
+func (ctrl *ContactsController) Show(c echo.Context) error   { return nil }
+func (ctrl *ContactsController) Index(c echo.Context) error  { return nil }
+func (ctrl *ContactsController) BeforeAction(c echo.Context) error { return nil }
+func (ctrl *ContactsController) Start(ctx context.Context) error { return nil }
+func (ctrl *ContactsController) helper(c echo.Context) error { return nil }
+func (ctrl *ContactsController) Count(c echo.Context) (int, error) { return 0, nil }
+
+type contactForm struct{}
+`,
+		"sessions.go": `package controller
+
+import (
+	"github.com/aneshas/loom"
+	e "github.com/labstack/echo/v4"
+)
+
+type (
+	// SessionsController keeps the current user in its fields
+	//
+	//loom:scoped
+	SessionsController struct {
+		loom.Controller
+	}
+
+	PagesController struct {
+		loom.Controller
+	}
+)
+
+func (ctrl *SessionsController) Create(c e.Context) error { return nil }
+func (ctrl *PagesController) Home(c e.Context) error      { return nil }
+`,
+		"users.go": `package controller
+
+import "github.com/aneshas/loom"
+
+//loom:scoped
+type UsersController struct {
+	loom.Controller
+}
+`,
+		"controller_gen.go": `package controller
+
+func Register() {}
+`,
+	})
+
+	controllers, err := scanControllers(dir)
+	if err != nil {
+		t.Fatalf("scanControllers() error = %v", err)
+	}
+
+	want := []boundController{
+		{Name: "ContactsController", Actions: []string{"Index", "Show"}},
+		{Name: "PagesController", Actions: []string{"Home"}},
+		{Name: "SessionsController", Actions: []string{"Create"}, Scoped: true},
+		{Name: "UsersController", Scoped: true},
+	}
+
+	if !reflect.DeepEqual(controllers, want) {
+		t.Errorf("scanControllers() = %+v, want %+v", controllers, want)
+	}
+}
+
+func TestScanControllers_HandWrittenRegister(t *testing.T) {
+	dir := writeControllerFiles(t, map[string]string{
+		"register.go": "package controller\n\nfunc Register() {}\n",
+	})
+
+	if _, err := scanControllers(dir); err == nil || !strings.Contains(err.Error(), "declares Register") {
+		t.Errorf("scanControllers() error = %v, want hand written Register reported", err)
+	}
+}
+
+func TestGenerateControllersFile(t *testing.T) {
+	src, err := generateControllersFile("controller", []boundController{
+		{Name: "ContactsController", Actions: []string{"Show"}},
+		{Name: "SessionsController", Scoped: true},
+	})
+	if err != nil {
+		t.Fatalf("generateControllersFile() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"\tloom.Register[*ContactsController](l)\n\tloom.Bind(l, map[string]loom.ActionFunc[*ContactsController]{\n\t\t\"Show\": (*ContactsController).Show,\n\t})\n",
+		"\tloom.RegisterScoped[*SessionsController](l)\n",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generateControllersFile() does not contain %q:\n%s", want, src)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"os"
+
+	"github.com/aneshas/loom"
+)
+
+// runDepsGraphCommand prints the dependency graph of the application in the given format
+func runDepsGraphCommand(format string) error {
+	report, err := inspectApp()
+	if err != nil {
+		return err
+	}
+
+	return loom.WriteDepGraph(os.Stdout, report.Deps, format)
+}
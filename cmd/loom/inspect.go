@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/aneshas/loom"
+)
+
+// inspectApp runs the application main package in inspect mode and returns its report.
+// In inspect mode Loom configures everything as usual, but instead of starting
+// the server it writes the report to the file pointed to by LOOM_INSPECT.
+func inspectApp() (*loom.InspectReport, error) {
+	mainPkg, err := findAppMain()
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := os.CreateTemp("", "loom-inspect-*.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create inspect report file: %w", err)
+	}
+
+	reportPath := out.Name()
+	out.Close()
+
+	defer os.Remove(reportPath)
+
+	cmd := exec.Command("go", "run", mainPkg)
+	cmd.Env = append(os.Environ(), loom.InspectEnv+"="+reportPath)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to run %s in inspect mode: %w", mainPkg, err)
+	}
+
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inspect report: %w", err)
+	}
+
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%s did not write an inspect report, does it call Loom.Run?", mainPkg)
+	}
+
+	var report loom.InspectReport
+
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse inspect report: %w", err)
+	}
+
+	return &report, nil
+}
+
+// findAppMain returns the application main package - the only package in ./cmd
+// other than seed
+func findAppMain() (string, error) {
+	matches, err := filepath.Glob(filepath.Join("cmd", "*", "main.go"))
+	if err != nil {
+		return "", err
+	}
+
+	var mains []string
+
+	for _, match := range matches {
+		dir := filepath.Dir(match)
+
+		if filepath.Base(dir) == "seed" {
+			continue
+		}
+
+		mains = append(mains, "./"+filepath.ToSlash(dir))
+	}
+
+	switch len(mains) {
+	case 0:
+		return "", fmt.Errorf("no main package found in ./cmd, run the command from the application root")
+	case 1:
+		return mains[0], nil
+	default:
+		return "", fmt.Errorf("found multiple main packages in ./cmd: %v", mains)
+	}
+}
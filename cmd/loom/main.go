@@ -7,7 +7,6 @@ import (
 	"path/filepath"
 
 	"github.com/aneshas/loom"
-	"github.com/aneshas/loom/internal/db"
 	"github.com/spf13/cobra"
 )
 
@@ -16,8 +15,15 @@ func main() {
 		Use:   "loom",
 		Short: "Loom is an opinionated MVC web framework for Go — fast, simple, structured.",
 		Long:  `Loom is a fast, convention-over-configuration web framework for Go — built to get you from zero to working, deployable app with minimal setup and maximum clarity. It enforces structure, favors simplicity, and helps you ship faster without sacrificing maintainability.`,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			env, _ := cmd.Flags().GetString("env")
+
+			setEnv(env)
+		},
 	}
 
+	rootCmd.PersistentFlags().StringP("env", "e", "", "Environment to load the config of (default: $LOOM_ENV or dev)")
+
 	newCmd := &cobra.Command{
 		Use:   "new [APP_NAME]",
 		Short: "Generate a new loom web application",
@@ -63,6 +69,30 @@ Example:
 		},
 	}
 
+	depsGraphCmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Print the dependency graph",
+		Long: `Run the application in inspect mode and print every dependency registered in Deps
+with its lifetime, where it was registered and the controllers and factories consuming it.
+The server is not started.
+
+Example:
+  loom deps graph
+  loom deps graph --format dot | dot -Tsvg > deps.svg`,
+		Run: func(cmd *cobra.Command, args []string) {
+			format, _ := cmd.Flags().GetString("format")
+
+			if err := runDepsGraphCommand(format); err != nil {
+				fmt.Printf("Error printing dependency graph: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	depsGraphCmd.Flags().StringP("format", "f", loom.GraphText, "Output format: text, json or dot")
+
+	depsCmd.AddCommand(depsGraphCmd)
+
 	runCmd := &cobra.Command{
 		Use:   "run",
 		Short: "Run the application",
@@ -97,47 +127,34 @@ Example:
 	}
 
 	migrateCmd := &cobra.Command{
-		Use:   "migrate [CONFIG]",
+		Use:   "migrate [ENV]",
 		Short: "Run database migrations",
-		Long: `Run all database migrations from ./internal/db/migrations directory.
+		Long: `Run all database migrations from ./internal/db/migrations directory, followed by
+the migrations of the modules added with Loom.Use in module order (see loom.ModuleMigrations).
+Modules are discovered by running the application in inspect mode, when the application
+does not run yet (eg. before its models are generated) use --skip-modules to only run
+the migrations of the application.
+With --db the migrations of a database declared in app.databases are run instead,
+from its own ./internal/db/<name>/migrations directory.
 The command will automatically detect whether to use SQLite or PostgreSQL based on the configuration.
-If no CONFIG argument is provided, it defaults to 'dev'.
+The ENV argument is a shorthand for --env, it defaults to $LOOM_ENV or 'dev'.
 
 Example:
   loom db migrate
-  loom db migrate [dev|production]`,
-		Args: cobra.ExactArgs(1),
+  loom db migrate prod
+  loom db migrate --env prod
+  loom db migrate --db analytics
+  loom db migrate --skip-modules`,
+		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			// configName := "dev" - TODO
-
-			// if len(args) > 0 {
-			// 	configName = args[0]
-			// }
-
-			// TODO - Set env somehow and make load config use it
-
-			cfg, err := loadConfig()
-			if err != nil {
-				fmt.Printf("Error loading config: %v\n", err)
-				os.Exit(1)
+			if len(args) > 0 {
+				setEnv(args[0])
 			}
 
-			// Check if migrations directory exists
-			migrationsPath := "./internal/db/migrations"
-			if _, err := os.Stat(migrationsPath); os.IsNotExist(err) {
-				fmt.Printf("Error: Migrations directory not found: %s\n", migrationsPath)
-				os.Exit(1)
-			}
+			dbName, _ := cmd.Flags().GetString("db")
+			skipModules, _ := cmd.Flags().GetBool("skip-modules")
 
-			// Convert to absolute path for migrate package
-			absMigrationsPath, err := filepath.Abs(migrationsPath)
-			if err != nil {
-				fmt.Printf("Error getting absolute path: %v\n", err)
-				os.Exit(1)
-			}
-
-			// Run migrations
-			if err := db.RunMigrations(cfg, absMigrationsPath); err != nil {
+			if err := runMigrateCommand(dbName, skipModules); err != nil {
 				fmt.Printf("Error running migrations: %v\n", err)
 				os.Exit(1)
 			}
@@ -150,9 +167,11 @@ Example:
 		Use:   "gen-migration description",
 		Short: "Generate a new migration file",
 		Long: `Generate a new migration file with the given description.
+With --db the migration is created for a database declared in app.databases.
 
 Example:
-  loom db gen-migration "Add users table"]`,
+  loom db gen-migration "Add users table"
+  loom db gen-migration "Add events table" --db analytics`,
 		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			if len(args) == 0 {
@@ -160,7 +179,9 @@ Example:
 				os.Exit(1)
 			}
 
-			err := db.GenMigration(args[0])
+			dbName, _ := cmd.Flags().GetString("db")
+
+			err := runGenMigrationCommand(dbName, args[0])
 			if err != nil {
 				fmt.Printf("Error generating migration: %v\n", err)
 				os.Exit(1)
@@ -175,14 +196,20 @@ Example:
 	// next - generate store based on the sqlboiler model
 
 	seedCmd := &cobra.Command{
-		Use:   "seed",
+		Use:   "seed [ENV]",
 		Short: "Run database seeders",
 		Long: `Run all database seeders from ./scripts/seed.go file.
+The ENV argument is a shorthand for --env, it defaults to $LOOM_ENV or 'dev'.
 
 Example:
   loom db seed
-  loom db seed [dev|production]`,
+  loom db seed prod`,
+		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) > 0 {
+				setEnv(args[0])
+			}
+
 			if err := os.MkdirAll("bin", 0o755); err != nil {
 				fmt.Printf("Error creating bin directory: %v\n", err)
 				os.Exit(1)
@@ -213,6 +240,10 @@ Example:
 		},
 	}
 
+	migrateCmd.Flags().String("db", "", "Named database to migrate (default: the default database)")
+	migrateCmd.Flags().Bool("skip-modules", false, "Only run the application migrations, without discovering the module migrations")
+	genMigrationCmd.Flags().String("db", "", "Named database to create the migration for (default: the default database)")
+
 	dbCmd.AddCommand(migrateCmd, genMigrationCmd, seedCmd)
 
 	scaffoldCmd := &cobra.Command{
@@ -237,7 +268,118 @@ Example:
 		},
 	}
 
-	rootCmd.AddCommand(newCmd, depsCmd, runCmd, dbCmd, scaffoldCmd)
+	genCmd := &cobra.Command{
+		Use:   "gen",
+		Short: "Code generators",
+		Long:  `Code generators that keep the application in sync with its configuration.`,
+	}
+
+	genRoutesCmd := &cobra.Command{
+		Use:   "routes",
+		Short: "Generate typed URL functions for every named route",
+		Long: `Run the application in inspect mode and generate web/routes/routes.go with
+a typed function per named route, so broken links fail at compile time.
+
+Example:
+  loom gen routes`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runGenRoutesCommand(); err != nil {
+				fmt.Printf("Error generating routes: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("✓ Routes generated successfully")
+		},
+	}
+
+	genControllersCmd := &cobra.Command{
+		Use:   "controllers",
+		Short: "Generate reflection-free controller bindings",
+		Long: `Scan web/controller and generate web/controller/controller_gen.go with the Register
+function, binding every func(echo.Context) error action directly so Loom does not
+have to call it through reflection. Other actions fall back to reflection.
+
+Example:
+  loom gen controllers`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runGenControllersCommand(); err != nil {
+				fmt.Printf("Error generating controllers: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("✓ Controllers generated successfully")
+		},
+	}
+
+	genCmd.AddCommand(genRoutesCmd, genControllersCmd)
+
+	routesCmd := &cobra.Command{
+		Use:   "routes",
+		Short: "Print the route table",
+		Long: `Run the application in inspect mode and print every route registered through Loom
+with its ctrlAction, resolved controller method and middleware chain.
+The server is not started.
+
+Example:
+  loom routes
+  loom routes --json > routes.json`,
+		Run: func(cmd *cobra.Command, args []string) {
+			asJSON, _ := cmd.Flags().GetBool("json")
+
+			if err := runRoutesCommand(asJSON); err != nil {
+				fmt.Printf("Error printing routes: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	routesCmd.Flags().Bool("json", false, "Print the routes as JSON")
+
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the application config",
+		Long:  `Inspect and validate the config files in ./config.`,
+	}
+
+	configShowCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Print the effective config",
+		Long: `Print the config LoadConfig produces for the environment, merged from base.yaml,
+<env>.yaml, <env>.local.yaml and environment variable overrides, with secret references resolved.
+Every value is annotated with its source, secret values are redacted.
+Settings the application does not declare in loom.AppConfig are printed as they are in the files.
+
+Example:
+  loom config show
+  loom config show --env prod`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runConfigShowCommand(); err != nil {
+				fmt.Printf("Error showing config: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	configCheckCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Validate the config of every environment",
+		Long: `Validate the config files of every environment in ./config without starting the application.
+Environment variable overrides are not applied and secret references that can't be resolved
+on this machine are not reported.
+
+Example:
+  loom config check`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runConfigCheckCommand(); err != nil {
+				fmt.Printf("Error checking config: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	configCmd.AddCommand(configShowCmd, configCheckCmd)
+
+	rootCmd.AddCommand(newCmd, depsCmd, runCmd, dbCmd, scaffoldCmd, genCmd, routesCmd, configCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -245,20 +387,36 @@ Example:
 	}
 }
 
+// cfg is the config of loom applications as far as the CLI knows it
 type cfg struct {
 	loom.AppConfig `yaml:"app"`
+
+	// Settings holds the application specific settings
+	Settings map[string]any `yaml:",inline"`
 }
 
 func loadConfig() (*loom.AppConfig, error) {
-	cfg, err := loom.LoadConfig[cfg]("./config")
+	cfg, err := loom.LoadConfig[cfg](configPath)
 	if err != nil {
-		fmt.Printf("Error loading config: %v\n", err)
-		os.Exit(1)
+		return nil, fmt.Errorf("%s: %w", loom.Env(), err)
 	}
 
 	return &cfg.AppConfig, nil
 }
 
+// setEnv selects the environment for the command and the application processes it starts
+// (inspect mode, seeders), which pick it up through loom.Env
+func setEnv(env string) {
+	if env == "" {
+		return
+	}
+
+	if err := os.Setenv(loom.EnvVar, env); err != nil {
+		fmt.Printf("Error setting environment: %v\n", err)
+		os.Exit(1)
+	}
+}
+
 func runDepsCommand(path string) error {
 	fmt.Println("Running dependencies installation...")
 
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/aneshas/loom"
+	"github.com/aneshas/loom/internal/db"
+)
+
+// appMigrationsPath is where the application migrations of the default database live
+const appMigrationsPath = "./internal/db/migrations"
+
+// migrationsPath returns the migrations directory of the named database,
+// the default database when name is empty: internal/db/analytics/migrations
+func migrationsPath(name string) string {
+	if name == "" {
+		return appMigrationsPath
+	}
+
+	return "./" + path.Join("internal/db", name, "migrations")
+}
+
+// databaseName returns the name of the database to run the migrations of, empty for the default database.
+// The primary database is the default one unless the config declares it in databases.
+func databaseName(cfg *loom.AppConfig, name string) (string, error) {
+	if _, ok := cfg.Databases[name]; ok {
+		return name, nil
+	}
+
+	if name == "" || name == loom.DBPrimary {
+		return "", nil
+	}
+
+	return "", fmt.Errorf("unknown database %s, declare it in app.databases", name)
+}
+
+// migrationSet is a migrations directory and the table its applied versions are tracked in
+type migrationSet struct {
+	path  string
+	table string
+}
+
+// runMigrateCommand runs the migrations of the named database, the migrations of the default
+// database are followed by the migrations of the application modules unless skipModules is set
+func runMigrateCommand(dbName string, skipModules bool) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	dbName, err = databaseName(cfg, dbName)
+	if err != nil {
+		return err
+	}
+
+	dbCfg, _ := cfg.Database(dbName)
+
+	sets := []migrationSet{{path: migrationsPath(dbName)}}
+
+	if _, err := os.Stat(sets[0].path); os.IsNotExist(err) {
+		return fmt.Errorf("migrations directory not found: %s", sets[0].path)
+	}
+
+	if dbName == "" && !skipModules {
+		moduleSets, err := moduleMigrations()
+		if err != nil {
+			return err
+		}
+
+		sets = append(sets, moduleSets...)
+	}
+
+	for _, set := range sets {
+		absPath, err := filepath.Abs(set.path)
+		if err != nil {
+			return fmt.Errorf("failed to get absolute path: %w", err)
+		}
+
+		if err := db.RunMigrations(dbCfg, absPath, set.table); err != nil {
+			return fmt.Errorf("%s: %w", set.path, err)
+		}
+	}
+
+	return nil
+}
+
+// runGenMigrationCommand creates a migration for the named database
+func runGenMigrationCommand(dbName string, description string) error {
+	if dbName != "" {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		dbName, err = databaseName(cfg, dbName)
+		if err != nil {
+			return err
+		}
+	}
+
+	return db.GenMigration(migrationsPath(dbName), description)
+}
+
+// moduleMigrations discovers the migrations of the modules added with Loom.Use,
+// each module tracks its versions in its own table since version numbers overlap between modules.
+// Discovery runs the app, when it fails (eg. the app does not compile until models are generated
+// from the migrated schema) nothing is migrated, --skip-modules runs the app migrations alone.
+func moduleMigrations() ([]migrationSet, error) {
+	report, err := inspectApp()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover the module migrations, use --skip-modules to only run the app migrations: %w", err)
+	}
+
+	var sets []migrationSet
+
+	for _, module := range report.Modules {
+		if module.Migrations == "" {
+			continue
+		}
+
+		if _, err := os.Stat(module.Migrations); os.IsNotExist(err) {
+			return nil, fmt.Errorf("migrations directory of module %s not found: %s", module.Name, module.Migrations)
+		}
+
+		sets = append(sets, migrationSet{
+			path:  module.Migrations,
+			table: "schema_migrations_" + tableName(module.Name),
+		})
+	}
+
+	return sets, nil
+}
+
+// tableName turns a module name into a safe table name suffix: "user-accounts" -> "user_accounts"
+func tableName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+
+		return '_'
+	}, name)
+}
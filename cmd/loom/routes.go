@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/aneshas/loom"
+)
+
+// runRoutesCommand prints the route table of the application as a table or as JSON
+func runRoutesCommand(asJSON bool) error {
+	report, err := inspectApp()
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		return writeRoutesJSON(os.Stdout, report.Routes)
+	}
+
+	return writeRoutesTable(os.Stdout, report.Routes)
+}
+
+func writeRoutesJSON(w io.Writer, routes []loom.RouteInfo) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(routes)
+}
+
+func writeRoutesTable(w io.Writer, routes []loom.RouteInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "METHOD\tPATH\tNAME\tCONTROLLER\tMIDDLEWARE")
+
+	for _, route := range routes {
+		middleware := strings.Join(route.Middleware, " -> ")
+		if middleware == "" {
+			middleware = "-"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s.%s\t%s\n",
+			route.Method, route.Path, route.Name, route.Controller, route.Action, middleware)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/aneshas/loom"
+)
+
+// runGenRoutesCommand generates web/routes/routes.go with a typed URL function per named route
+func runGenRoutesCommand() error {
+	report, err := inspectApp()
+	if err != nil {
+		return err
+	}
+
+	src, err := generateRoutesFile(report.Routes)
+	if err != nil {
+		return err
+	}
+
+	outputDir := filepath.Join("web", "routes")
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(outputDir, "routes.go"), src, 0o644); err != nil {
+		return fmt.Errorf("failed to write routes file: %w", err)
+	}
+
+	return nil
+}
+
+// generateRoutesFile renders the routes package source:
+//
+//	// ContactsShow returns the path of the contacts.show route: GET /contacts/:id
+//	func ContactsShow(id any) string {
+//		return "/contacts/" + param(id)
+//	}
+func generateRoutesFile(routes []loom.RouteInfo) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString("// Code generated by loom gen routes. DO NOT EDIT.\n\n")
+	buf.WriteString("package routes\n\n")
+	buf.WriteString("import (\n\t\"fmt\"\n\t\"net/url\"\n)\n")
+
+	seen := make(map[string]loom.RouteInfo)
+
+	for _, route := range routes {
+		funcName := routeFuncName(route.Name)
+
+		// PUT and PATCH of the same resource action share the name and path
+		if other, ok := seen[funcName]; ok {
+			if other.Path != route.Path {
+				return nil, fmt.Errorf("routes %s %s and %s %s both generate %s, give them different names",
+					other.Method, other.Path, route.Method, route.Path, funcName)
+			}
+
+			continue
+		}
+
+		seen[funcName] = route
+
+		params := pathParamNames(route.Path)
+
+		fmt.Fprintf(&buf, "\n// %s returns the path of the %s route: %s %s\n", funcName, route.Name, route.Method, route.Path)
+		fmt.Fprintf(&buf, "func %s(", funcName)
+
+		if len(params) > 0 {
+			fmt.Fprintf(&buf, "%s any", strings.Join(params, ", "))
+		}
+
+		fmt.Fprintf(&buf, ") string {\n\treturn %s\n}\n", routePathExpr(route.Path, params))
+	}
+
+	buf.WriteString(`
+func param(v any) string {
+	return url.PathEscape(fmt.Sprint(v))
+}
+`)
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format routes file: %w", err)
+	}
+
+	return src, nil
+}
+
+// routePathExpr builds the string concatenation expression for a route path
+func routePathExpr(path string, params []string) string {
+	var (
+		parts   []string
+		literal strings.Builder
+		n       int
+	)
+
+	flush := func() {
+		if literal.Len() > 0 {
+			parts = append(parts, fmt.Sprintf("%q", literal.String()))
+			literal.Reset()
+		}
+	}
+
+	for i, segment := range strings.Split(path, "/") {
+		if i > 0 {
+			literal.WriteString("/")
+		}
+
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			flush()
+			parts = append(parts, "param("+params[n]+")")
+			n++
+		case strings.HasPrefix(segment, "*"):
+			flush()
+			parts = append(parts, "fmt.Sprint("+params[n]+")")
+			n++
+		default:
+			literal.WriteString(segment)
+		}
+	}
+
+	flush()
+
+	if len(parts) == 0 {
+		return `""`
+	}
+
+	return strings.Join(parts, " + ")
+}
+
+// pathParamNames returns the route path params as Go identifiers:
+// "/contacts/:contact_id/notes/:id" -> ["contactID", "id"]
+func pathParamNames(path string) []string {
+	var names []string
+
+	for _, param := range loom.PathParams(path) {
+		if param == "*" {
+			param = "wildcard"
+		}
+
+		names = append(names, goIdent(param, false))
+	}
+
+	return names
+}
+
+// routeFuncName converts a route name to an exported function name:
+// "contacts.show" -> "ContactsShow", "pages.not_found" -> "PagesNotFound"
+func routeFuncName(name string) string {
+	return goIdent(strings.ReplaceAll(name, ".", "_"), true)
+}
+
+// goIdent converts a snake_case name to a camel case Go identifier
+func goIdent(name string, exported bool) string {
+	var b strings.Builder
+
+	for i, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if i == 0 && !exported {
+			b.WriteString(strings.ToLower(part[:1]) + part[1:])
+			continue
+		}
+
+		if strings.EqualFold(part, "id") {
+			b.WriteString("ID")
+			continue
+		}
+
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aneshas/loom"
+)
+
+func TestRoutePathExpr(t *testing.T) {
+	tests := []struct {
+		path   string
+		params []string
+		want   string
+	}{
+		{"/", nil, `"/"`},
+		{"/contacts", nil, `"/contacts"`},
+		{"/contacts/:id", []string{"id"}, `"/contacts/" + param(id)`},
+		{"/contacts/:contact_id/notes/:id/edit", []string{"contactID", "id"}, `"/contacts/" + param(contactID) + "/notes/" + param(id) + "/edit"`},
+		{"/files/*", []string{"wildcard"}, `"/files/" + fmt.Sprint(wildcard)`},
+	}
+
+	for _, tt := range tests {
+		if got := routePathExpr(tt.path, pathParamNames(tt.path)); got != tt.want {
+			t.Errorf("routePathExpr(%s) = %v, want %v", tt.path, got, tt.want)
+		}
+
+		if got := pathParamNames(tt.path); !reflect.DeepEqual(got, tt.params) {
+			t.Errorf("pathParamNames(%s) = %v, want %v", tt.path, got, tt.params)
+		}
+	}
+}
+
+func TestGoIdent(t *testing.T) {
+	tests := []struct {
+		name     string
+		exported bool
+		want     string
+	}{
+		{"contact_id", false, "contactID"},
+		{"id", false, "id"},
+		{"user_id", true, "UserID"},
+		{"contacts_show", true, "ContactsShow"},
+		{"pages_not_found", true, "PagesNotFound"},
+		{"api_v2", false, "apiV2"},
+	}
+
+	for _, tt := range tests {
+		if got := goIdent(tt.name, tt.exported); got != tt.want {
+			t.Errorf("goIdent(%s, %v) = %v, want %v", tt.name, tt.exported, got, tt.want)
+		}
+	}
+
+	if got := routeFuncName("users.by_id"); got != "UsersByID" {
+		t.Errorf("routeFuncName(users.by_id) = %v, want UsersByID", got)
+	}
+}
+
+func TestGenerateRoutesFile(t *testing.T) {
+	routes := []loom.RouteInfo{
+		{Method: "GET", Path: "/contacts/:id", Name: "contacts.show"},
+		{Method: "PUT", Path: "/contacts/:id", Name: "contacts.update"},
+		{Method: "PATCH", Path: "/contacts/:id", Name: "contacts.update"},
+		{Method: "GET", Path: "/files/*", Name: "files.show"},
+	}
+
+	src, err := generateRoutesFile(routes)
+	if err != nil {
+		t.Fatalf("generateRoutesFile() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"func ContactsShow(id any) string {\n\treturn \"/contacts/\" + param(id)\n}",
+		"func FilesShow(wildcard any) string {\n\treturn \"/files/\" + fmt.Sprint(wildcard)\n}",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generateRoutesFile() does not contain %q:\n%s", want, src)
+		}
+	}
+
+	// PUT and PATCH share the function
+	if n := strings.Count(string(src), "func ContactsUpdate("); n != 1 {
+		t.Errorf("generateRoutesFile() declares ContactsUpdate %d times, want 1", n)
+	}
+}
+
+func TestGenerateRoutesFile_NameClash(t *testing.T) {
+	routes := []loom.RouteInfo{
+		{Method: "GET", Path: "/contacts/:id", Name: "contacts.show"},
+		{Method: "GET", Path: "/admin/contacts/:id", Name: "contacts.show"},
+	}
+
+	_, err := generateRoutesFile(routes)
+	if err == nil || !strings.Contains(err.Error(), "/admin/contacts/:id") {
+		t.Errorf("generateRoutesFile() error = %v, want the clashing routes reported", err)
+	}
+}
@@ -0,0 +1,240 @@
+package loom
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigSetting is a setting of a loaded config along with where its value comes from
+type ConfigSetting struct {
+	// Path is the yaml path of the setting: "app.db.host"
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+
+	// Source is the config file or the environment variable setting the value,
+	// "default" when the value is not set
+	Source string `json:"source"`
+
+	// Ref is the secret reference the value was resolved from: "${env:DB_PASSWORD}",
+	// "unresolved" when the reference could not be resolved and the value is the reference itself
+	Ref string `json:"ref,omitempty"`
+
+	// Redacted reports whether the value was hidden, values of fields tagged with
+	// `loom:"secret"` and values resolved from secret references are redacted
+	Redacted bool `json:"redacted"`
+}
+
+// redacted replaces the value of secret settings
+const redacted = "<redacted>"
+
+// ExplainConfig loads the config of env the way LoadConfigEnv does, without validating it,
+// and lists its settings in declaration order along with their source.
+// Secret references that can't be resolved are kept as they are and their Ref is "unresolved".
+// Usage: settings, err := loom.ExplainConfig[config.Config]("./config", "prod")
+func ExplainConfig[TConfig any](configPath string, env string) ([]ConfigSetting, error) {
+	var config TConfig
+
+	loader := configLoader{
+		path:           configPath,
+		env:            env,
+		lenientSecrets: true,
+		sources:        make(map[string]*configSource),
+	}
+
+	if err := loader.load(&config); err != nil {
+		return nil, err
+	}
+
+	var settings []ConfigSetting
+
+	walkConfig(reflect.ValueOf(config), nil, false, func(path string, v reflect.Value, secret bool) {
+		setting := ConfigSetting{
+			Path:   path,
+			Source: "default",
+		}
+
+		if v.IsValid() {
+			setting.Value = v.Interface()
+		}
+
+		if src, ok := loader.sources[path]; ok {
+			setting.Source = src.origin
+			setting.Ref = src.ref
+		}
+
+		// unresolvable references are left in place
+		if setting.Ref != "" && setting.Value == setting.Ref {
+			setting.Ref = "unresolved"
+		}
+
+		if (secret || setting.Ref != "" && setting.Ref != "unresolved") && v.IsValid() && !v.IsZero() {
+			setting.Value = redacted
+			setting.Redacted = true
+		}
+
+		settings = append(settings, setting)
+	})
+
+	return settings, nil
+}
+
+// walkConfig calls fn with every setting of a config, lists and values implementing
+// yaml.Unmarshaler are settings of their own
+func walkConfig(v reflect.Value, path []string, secret bool, fn func(path string, v reflect.Value, secret bool)) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			walkConfig(v.Elem(), path, secret, fn)
+			return
+		}
+	case reflect.Struct:
+		if len(path) > 0 && reflect.PointerTo(v.Type()).Implements(yamlUnmarshalerType) {
+			break
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+
+			key, inline, ok := yamlField(field)
+			if !ok {
+				continue
+			}
+
+			fieldPath := append(slices.Clip(path), key)
+			if inline {
+				fieldPath = path
+			}
+
+			walkConfig(v.Field(i), fieldPath, secret || field.Tag.Get("loom") == "secret", fn)
+		}
+
+		return
+	case reflect.Map:
+		if v.Len() == 0 {
+			break
+		}
+
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
+		})
+
+		for _, key := range keys {
+			walkConfig(v.MapIndex(key), append(slices.Clip(path), fmt.Sprint(key)), secret, fn)
+		}
+
+		return
+	}
+
+	if v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
+		v = reflect.Value{}
+	}
+
+	fn(strings.Join(path, "."), v, secret)
+}
+
+// WriteConfigSettings writes the settings as YAML, annotating every value with its source
+//
+//	app:
+//	  host: :8080 # base.yaml
+//	  db:
+//	    password: <redacted> # prod.yaml ${env:DB_PASSWORD}
+func WriteConfigSettings(w io.Writer, settings []ConfigSetting) error {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+
+	for _, setting := range settings {
+		parent := root
+		keys := strings.Split(setting.Path, ".")
+
+		for _, key := range keys[:len(keys)-1] {
+			parent = mappingChild(parent, key)
+		}
+
+		value := &yaml.Node{}
+		if err := value.Encode(setting.Value); err != nil {
+			return fmt.Errorf("failed to encode %s: %w", setting.Path, err)
+		}
+
+		value.LineComment = strings.TrimSpace(setting.Source + " " + setting.Ref)
+
+		parent.Content = append(parent.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: keys[len(keys)-1]},
+			value,
+		)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+// mappingChild returns the mapping under key, adding it to parent when missing
+func mappingChild(parent *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == key {
+			return parent.Content[i+1]
+		}
+	}
+
+	child := &yaml.Node{Kind: yaml.MappingNode}
+
+	parent.Content = append(parent.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, child)
+
+	return child
+}
+
+// CheckConfig validates the config files of env, environment variable overrides are not applied.
+// Secret references that can't be resolved are not reported, nor is the validation of their settings,
+// as secrets are usually only available where the application is deployed.
+// Usage: err := loom.CheckConfig[config.Config]("./config", "prod")
+func CheckConfig[TConfig any](configPath string, env string) error {
+	var config TConfig
+
+	loader := configLoader{
+		path:           configPath,
+		env:            env,
+		skipEnv:        true,
+		lenientSecrets: true,
+	}
+
+	if err := loader.load(&config); err != nil {
+		return err
+	}
+
+	return dropUnresolved(ValidateConfig(&config), loader.unresolved)
+}
+
+// ConfigEnvs lists the environments with a config file in configPath: dev.yaml, prod.yaml -> dev, prod
+func ConfigEnvs(configPath string) ([]string, error) {
+	entries, err := os.ReadDir(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config directory: %w", err)
+	}
+
+	var envs []string
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if entry.IsDir() || filepath.Ext(name) != ".yaml" || name == "base.yaml" || strings.HasSuffix(name, ".local.yaml") {
+			continue
+		}
+
+		envs = append(envs, strings.TrimSuffix(name, ".yaml"))
+	}
+
+	return envs, nil
+}
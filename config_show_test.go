@@ -0,0 +1,109 @@
+package loom
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type showConfig struct {
+	AppConfig `yaml:"app"`
+	Mail      struct {
+		APIKey string `yaml:"api_key" loom:"secret"`
+	} `yaml:"mail"`
+	Settings map[string]any `yaml:",inline"`
+}
+
+func TestExplainConfig(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"base.yaml": "app:\n  host: \":8080\"\nmail:\n  api_key: key\nfeature:\n  token: ${env:TEST_FEATURE_TOKEN}\n",
+		"prod.yaml": "app:\n  db:\n    host: db\n    password: ${env:TEST_DB_PASSWORD}\n",
+	})
+
+	t.Setenv("TEST_DB_PASSWORD", "secret")
+	t.Setenv("APP_DB_PORT", "6432")
+
+	settings, err := ExplainConfig[showConfig](dir, "prod")
+	if err != nil {
+		t.Fatalf("ExplainConfig() error = %v", err)
+	}
+
+	got := make(map[string]ConfigSetting, len(settings))
+	for _, s := range settings {
+		got[s.Path] = s
+	}
+
+	want := []ConfigSetting{
+		{Path: "app.db.host", Value: "db", Source: "prod.yaml"},
+		{Path: "app.db.port", Value: 6432, Source: "$APP_DB_PORT"},
+		{Path: "app.db.password", Value: redacted, Source: "prod.yaml", Ref: "${env:TEST_DB_PASSWORD}", Redacted: true},
+		{Path: "app.db.name", Value: "", Source: "default"},
+		{Path: "app.host", Value: ":8080", Source: "base.yaml"},
+		{Path: "mail.api_key", Value: redacted, Source: "base.yaml", Redacted: true},
+		{Path: "feature.token", Value: "${env:TEST_FEATURE_TOKEN}", Source: "base.yaml", Ref: "unresolved"},
+	}
+
+	for _, w := range want {
+		if got[w.Path] != w {
+			t.Errorf("ExplainConfig() %s = %+v, want %+v", w.Path, got[w.Path], w)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := WriteConfigSettings(&out, settings); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		"    password: <redacted> # prod.yaml ${env:TEST_DB_PASSWORD}\n",
+		"    port: 6432 # $APP_DB_PORT\n",
+		"  token: ${env:TEST_FEATURE_TOKEN} # base.yaml unresolved\n",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("WriteConfigSettings() does not contain %q:\n%s", line, out.String())
+		}
+	}
+}
+
+func TestCheckConfig(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"base.yaml":      "host: \":8080\"\n",
+		"dev.yaml":       "db:\n  name: dev\n",
+		"dev.local.yaml": "debug: true\n",
+		"prod.yaml":      "db:\n  host: db\n  user: app\n  password: ${env:TEST_UNSET_PASSWORD}\n",
+		"staging.yaml":   "db:\n  host: db\n",
+		"test.yaml":      "host: \":8080\"\ndb:\n  url: ${env:TEST_UNSET_DATABASE_URL}\n",
+	})
+
+	envs, err := ConfigEnvs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(envs, ",") != "dev,prod,staging,test" {
+		t.Errorf("ConfigEnvs() = %v, want [dev prod staging test]", envs)
+	}
+
+	if err := CheckConfig[AppConfig](dir, "dev"); err != nil {
+		t.Errorf("CheckConfig(dev) error = %v", err)
+	}
+
+	// the unresolved password is not reported, the missing name is
+	err = CheckConfig[AppConfig](dir, "prod")
+	if err == nil || err.Error() != "invalid config:\n  db.name: is required when url is not set" {
+		t.Errorf("CheckConfig(prod) error = %v", err)
+	}
+
+	// the unresolved url is not validated
+	if err := CheckConfig[AppConfig](dir, "test"); err != nil {
+		t.Errorf("CheckConfig(test) error = %v", err)
+	}
+
+	// environment variable overrides are not applied
+	t.Setenv("DB_NAME", "app")
+
+	err = CheckConfig[AppConfig](dir, "staging")
+	if err == nil || !strings.Contains(err.Error(), "db.user") || !strings.Contains(err.Error(), "db.name") {
+		t.Errorf("CheckConfig(staging) error = %v, want missing user and name", err)
+	}
+}
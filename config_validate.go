@@ -0,0 +1,339 @@
+package loom
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ConfigError reports every problem found in a loaded config
+type ConfigError struct {
+	Problems []ConfigProblem
+}
+
+// ConfigProblem is a problem with a single setting
+type ConfigProblem struct {
+	// Path is the yaml path of the setting: "app.db.password"
+	Path    string
+	Message string
+}
+
+func (e *ConfigError) Error() string {
+	var b strings.Builder
+
+	b.WriteString("invalid config:")
+
+	for _, p := range e.Problems {
+		fmt.Fprintf(&b, "\n  %s: %s", p.Path, p.Message)
+	}
+
+	return b.String()
+}
+
+var configValidator = sync.OnceValue(func() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// dburl validates database urls, see DBConfig.URL
+	v.RegisterValidation("dburl", func(fl validator.FieldLevel) bool {
+		_, err := DBConfig{URL: fl.Field().String()}.parseURL()
+		return err == nil
+	})
+
+	return v
+})
+
+// ValidateConfig validates the config with the validate tags of its fields, the same way
+// Controller validates forms. All problems are reported at once in a *ConfigError.
+// LoadConfig validates the configs it loads.
+//
+//	type Config struct {
+//		loom.AppConfig `yaml:"app"`
+//		SMTP struct {
+//			Host string `yaml:"host" validate:"required"`
+//		} `yaml:"smtp"`
+//	}
+func ValidateConfig(config any) error {
+	err := configValidator().Struct(config)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return err
+	}
+
+	t := reflect.TypeOf(config)
+
+	cerr := &ConfigError{}
+
+	for _, fe := range fieldErrors {
+		path, parent := configPath(t, fe.StructNamespace())
+
+		cerr.Problems = append(cerr.Problems, ConfigProblem{
+			Path:    path,
+			Message: configProblemMessage(fe, parent),
+		})
+	}
+
+	return cerr
+}
+
+// configPath converts the struct namespace of a validation error, eg. "Config.AppConfig.DB.Password",
+// to its yaml path "app.db.password" and returns the type of the struct holding the field.
+// Map entries are nested keys while list items are indexed: "databases.primary", "brokers[1]"
+func configPath(t reflect.Type, namespace string) (string, reflect.Type) {
+	segments := strings.Split(namespace, ".")[1:]
+
+	var (
+		path   []string
+		parent = derefType(t)
+	)
+
+	for i, segment := range segments {
+		name, index, _ := strings.Cut(segment, "[")
+		if index != "" {
+			index = "[" + index
+		}
+
+		field, ok := parent.FieldByName(name)
+		if !ok {
+			return strings.Join(append(path, segments[i:]...), "."), parent
+		}
+
+		key, inline, _ := yamlField(field)
+
+		// map entries are nested keys in yaml: databases.primary
+		if index != "" && derefType(field.Type).Kind() == reflect.Map {
+			path = append(path, key, strings.Trim(index, "[]"))
+		} else if !inline {
+			path = append(path, key+index)
+		}
+
+		if i == len(segments)-1 {
+			break
+		}
+
+		next := derefType(field.Type)
+		for range strings.Count(index, "[") {
+			next = derefType(next.Elem())
+		}
+
+		parent = next
+	}
+
+	return strings.Join(path, "."), parent
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+func configProblemMessage(fe validator.FieldError, parent reflect.Type) string {
+	param := fe.Param()
+
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "required_with", "required_with_all", "required_without", "required_without_all":
+		// the param lists sibling fields by their Go name
+		keys := strings.Fields(param)
+		for i, name := range keys {
+			if field, ok := parent.FieldByName(name); ok {
+				keys[i], _, _ = yamlField(field)
+			}
+		}
+
+		if strings.HasPrefix(fe.Tag(), "required_without") {
+			return fmt.Sprintf("is required when %s is not set", strings.Join(keys, ", "))
+		}
+
+		return fmt.Sprintf("is required when %s is set", strings.Join(keys, ", "))
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(param), ", ")
+	case "min", "gte":
+		return "must be at least " + param
+	case "max", "lte":
+		return "must be at most " + param
+	case "url":
+		return "must be a valid URL"
+	case "dburl":
+		_, err := DBConfig{URL: fmt.Sprint(fe.Value())}.parseURL()
+		return err.Error()
+	}
+
+	if param != "" {
+		return fmt.Sprintf("failed the %s=%s validation", fe.Tag(), param)
+	}
+
+	return fmt.Sprintf("failed the %s validation", fe.Tag())
+}
+
+// dropUnresolved removes the problems of the settings whose secret references were left
+// in place from a *ConfigError, their value is the reference so validating it is meaningless
+func dropUnresolved(err error, unresolved map[string]bool) error {
+	var cerr *ConfigError
+	if !errors.As(err, &cerr) || len(unresolved) == 0 {
+		return err
+	}
+
+	problems := slices.DeleteFunc(slices.Clone(cerr.Problems), func(p ConfigProblem) bool {
+		return unresolved[p.Path]
+	})
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return &ConfigError{Problems: problems}
+}
+
+// secretRef matches secret references: ${env:DB_PASSWORD} and ${file:/run/secrets/db}
+var secretRef = regexp.MustCompile(`\$\{(env|file):([^}]+)\}`)
+
+// secretResolver replaces the secret references in the string settings of a config with
+// the value of the environment variable or the content of the file they refer to,
+// so config files don't have to contain credentials. Trailing newlines of files are trimmed.
+type secretResolver struct {
+	// lenient leaves unresolvable references in place instead of reporting them
+	lenient bool
+
+	// refs are the resolved references by the yaml path of the setting
+	refs map[string]string
+
+	// unresolved are the yaml paths of the settings whose references were left in place
+	unresolved map[string]bool
+
+	problems []ConfigProblem
+}
+
+// resolve resolves the references of config, unresolvable references are reported in a *ConfigError
+func (r *secretResolver) resolve(config any) error {
+	r.refs = make(map[string]string)
+	r.unresolved = make(map[string]bool)
+
+	r.resolveIn(reflect.ValueOf(config), nil)
+
+	if len(r.problems) > 0 {
+		return &ConfigError{Problems: r.problems}
+	}
+
+	return nil
+}
+
+func (r *secretResolver) resolveIn(v reflect.Value, path []string) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			r.resolveIn(v.Elem(), path)
+		}
+	case reflect.Interface:
+		if v.IsNil() || !v.CanSet() {
+			return
+		}
+
+		// values held by interfaces are not addressable, resolve a copy and store it back
+		value := reflect.New(v.Elem().Type()).Elem()
+		value.Set(v.Elem())
+
+		r.resolveIn(value, path)
+
+		v.Set(value)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			key, inline, ok := yamlField(v.Type().Field(i))
+			if !ok {
+				continue
+			}
+
+			fieldPath := append(slices.Clip(path), key)
+			if inline {
+				fieldPath = path
+			}
+
+			r.resolveIn(v.Field(i), fieldPath)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			r.resolveIn(v.Index(i), indexPath(path, i))
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			value := reflect.New(iter.Value().Type()).Elem()
+			value.Set(iter.Value())
+
+			r.resolveIn(value, append(slices.Clip(path), fmt.Sprint(iter.Key())))
+
+			v.SetMapIndex(iter.Key(), value)
+		}
+	case reflect.String:
+		if !v.CanSet() || !secretRef.MatchString(v.String()) {
+			return
+		}
+
+		p := strings.Join(path, ".")
+		ref := v.String()
+
+		resolved := secretRef.ReplaceAllStringFunc(ref, func(match string) string {
+			m := secretRef.FindStringSubmatch(match)
+
+			value, err := resolveSecret(m[1], m[2])
+			if err != nil {
+				r.unresolved[p] = true
+
+				if !r.lenient {
+					r.problems = append(r.problems, ConfigProblem{Path: p, Message: err.Error()})
+				}
+
+				return match
+			}
+
+			return value
+		})
+
+		v.SetString(resolved)
+		r.refs[p] = ref
+	}
+}
+
+// indexPath appends the index of a list item to the last segment of path: brokers[1]
+func indexPath(path []string, index int) []string {
+	if len(path) == 0 {
+		return []string{fmt.Sprintf("[%d]", index)}
+	}
+
+	return append(slices.Clip(path[:len(path)-1]), fmt.Sprintf("%s[%d]", path[len(path)-1], index))
+}
+
+func resolveSecret(source, name string) (string, error) {
+	switch source {
+	case "env":
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+
+		return value, nil
+	case "file":
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file: %w", err)
+		}
+
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+
+	return "", fmt.Errorf("unknown secret source %s", source)
+}
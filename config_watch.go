@@ -0,0 +1,151 @@
+package loom
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultConfigWatchInterval is how often WatchConfig checks the config files when no interval is given
+const DefaultConfigWatchInterval = time.Second
+
+// ConfigHolder holds the current config of the application, WatchConfig swaps it atomically
+// when the config files change so readers always see a complete config.
+// Readers should call Get every time they need a value instead of keeping the config around.
+//
+//	type FeaturesController struct {
+//		loom.Controller
+//
+//		Config *loom.ConfigHolder[config.Config] `loom:"inject"`
+//	}
+//
+//	if ctrl.Config.Get().Features.Search { ... }
+type ConfigHolder[T any] struct {
+	current atomic.Pointer[T]
+
+	mu          sync.Mutex
+	subscribers []*configSubscriber[T]
+}
+
+type configSubscriber[T any] struct {
+	fn func(config *T)
+}
+
+// NewConfigHolder returns a holder of the loaded config
+// Usage: holder := loom.NewConfigHolder(cfg)
+func NewConfigHolder[T any](config *T) *ConfigHolder[T] {
+	h := &ConfigHolder[T]{}
+	h.current.Store(config)
+
+	return h
+}
+
+// Get returns the current config
+func (h *ConfigHolder[T]) Get() *T {
+	return h.current.Load()
+}
+
+// Set replaces the current config and notifies the subscribers in subscription order
+func (h *ConfigHolder[T]) Set(config *T) {
+	h.current.Store(config)
+
+	h.mu.Lock()
+	subscribers := slices.Clone(h.subscribers)
+	h.mu.Unlock()
+
+	for _, s := range subscribers {
+		s.fn(config)
+	}
+}
+
+// Subscribe registers fn to be called with the new config every time it is replaced,
+// it returns a function removing the subscription.
+// Usage: holder.Subscribe(func(cfg *config.Config) { logLevel.Set(cfg.LogLevel) })
+func (h *ConfigHolder[T]) Subscribe(fn func(config *T)) (unsubscribe func()) {
+	s := &configSubscriber[T]{fn: fn}
+
+	h.mu.Lock()
+	h.subscribers = append(h.subscribers, s)
+	h.mu.Unlock()
+
+	return func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		h.subscribers = slices.DeleteFunc(h.subscribers, func(other *configSubscriber[T]) bool {
+			return other == s
+		})
+	}
+}
+
+// WatchConfig reloads the config of the current environment (see LoadConfig) into holder whenever
+// one of its files changes, until ctx is done. The files are polled every interval, or every
+// DefaultConfigWatchInterval when it is zero. It is meant for development, so reload errors such as
+// invalid YAML or failed validation are logged and the previous config is kept.
+//
+//	if cfg.Debug {
+//		l.OnStart(func(ctx context.Context) error {
+//			go loom.WatchConfig(ctx, holder, "./config", 0)
+//			return nil
+//		})
+//	}
+func WatchConfig[T any](ctx context.Context, holder *ConfigHolder[T], configPath string, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultConfigWatchInterval
+	}
+
+	env := Env()
+	last := configFingerprint(configPath, env)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		fingerprint := configFingerprint(configPath, env)
+		if fingerprint == last {
+			continue
+		}
+
+		last = fingerprint
+
+		config, err := LoadConfigEnv[T](configPath, env)
+		if err != nil {
+			log.Printf("loom: failed to reload %s config, keeping the previous one: %v", env, err)
+			continue
+		}
+
+		holder.Set(config)
+
+		log.Printf("loom: reloaded %s config", env)
+	}
+}
+
+// configFingerprint identifies the state of the config files of env by their size and modification time
+func configFingerprint(configPath string, env string) string {
+	var b strings.Builder
+
+	for _, file := range []string{"base.yaml", env + ".yaml", env + ".local.yaml"} {
+		info, err := os.Stat(path.Join(configPath, file))
+		if err != nil {
+			b.WriteString("-;")
+			continue
+		}
+
+		fmt.Fprintf(&b, "%d:%d;", info.Size(), info.ModTime().UnixNano())
+	}
+
+	return b.String()
+}
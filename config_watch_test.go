@@ -0,0 +1,95 @@
+package loom
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestConfigHolder_Subscribe(t *testing.T) {
+	holder := NewConfigHolder(&AppConfig{Host: ":8080"})
+
+	var got []string
+
+	unsubscribe := holder.Subscribe(func(cfg *AppConfig) {
+		got = append(got, cfg.Host)
+	})
+
+	holder.Set(&AppConfig{Host: ":9090"})
+
+	unsubscribe()
+
+	holder.Set(&AppConfig{Host: ":7070"})
+
+	if len(got) != 1 || got[0] != ":9090" {
+		t.Errorf("subscriber got %v, want [:9090]", got)
+	}
+
+	if holder.Get().Host != ":7070" {
+		t.Errorf("Get().Host = %v, want :7070", holder.Get().Host)
+	}
+}
+
+func TestWatchConfig(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"dev.yaml": "host: \":8080\"\ndb:\n  name: app\n",
+	})
+
+	t.Setenv(EnvVar, "dev")
+
+	cfg, err := LoadConfig[AppConfig](dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	holder := NewConfigHolder(cfg)
+
+	reloaded := make(chan *AppConfig, 1)
+	holder.Subscribe(func(cfg *AppConfig) {
+		reloaded <- cfg
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+
+	go func() {
+		WatchConfig(ctx, holder, dir, 10*time.Millisecond)
+		close(done)
+	}()
+
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	write := func(file, data string) {
+		t.Helper()
+
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// an invalid config is logged and the previous one is kept
+	write("dev.yaml", "host: \":9090\"\n")
+	time.Sleep(50 * time.Millisecond)
+
+	if holder.Get().Host != ":8080" {
+		t.Fatalf("Get().Host = %v, want the previous config to be kept", holder.Get().Host)
+	}
+
+	write("dev.local.yaml", "host: \":9090\"\n")
+	write("dev.yaml", "host: \":8080\"\ndb:\n  name: app\n")
+
+	select {
+	case cfg := <-reloaded:
+		if cfg.Host != ":9090" || holder.Get() != cfg {
+			t.Errorf("reloaded config = %+v, want host :9090 from dev.local.yaml", cfg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("config was not reloaded")
+	}
+}
@@ -0,0 +1,78 @@
+package loom
+
+import (
+	"database/sql"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Labels of the databases routed to by Controller.WriteDB and Controller.ReadDB
+const (
+	DBPrimary = "primary"
+	DBReplica = "replica"
+)
+
+// Database returns the config of the named database declared in Databases,
+// DBPrimary is the default database DB unless Databases declares it
+func (c *AppConfig) Database(name string) (DBConfig, bool) {
+	if db, ok := c.Databases[name]; ok {
+		return db, true
+	}
+
+	if name == DBPrimary || name == "" {
+		return c.DB, true
+	}
+
+	return DBConfig{}, false
+}
+
+// AddDatabases opens the databases declared in Databases and adds them to Deps labeled
+// with their name, Loom closes them on shutdown. The default database is not added.
+//
+//	databases:
+//	  replica:
+//	    name: "sqlite"
+//	    read_only: true
+//
+// Usage: check(cfg.AddDatabases(deps))
+func (c *AppConfig) AddDatabases(d *Deps) error {
+	source := callerSource(1)
+
+	for _, name := range slices.Sorted(maps.Keys(c.Databases)) {
+		db, err := c.Databases[name].Open()
+		if err != nil {
+			return fmt.Errorf("failed to open %s database: %w", name, err)
+		}
+
+		d.set(getType[*sql.DB](), name, db, source)
+	}
+
+	return nil
+}
+
+// DBFor returns the *sql.DB registered with the label name, DBPrimary falls back
+// to the unlabeled *sql.DB when no database is labeled primary
+// Usage: db, err := ctrl.DBFor("analytics")
+func (cont *Controller) DBFor(name string) (*sql.DB, error) {
+	if name == DBPrimary && !HasWithLabel[*sql.DB](cont.Deps, DBPrimary) {
+		return Get[*sql.DB](cont.Deps)
+	}
+
+	return GetWithLabel[*sql.DB](cont.Deps, name)
+}
+
+// WriteDB returns the primary database (see DBFor)
+func (cont *Controller) WriteDB() (*sql.DB, error) {
+	return cont.DBFor(DBPrimary)
+}
+
+// ReadDB returns the replica database if one is registered, the primary database otherwise.
+// Reads that must see the request's own writes should use WriteDB, as replicas lag behind.
+func (cont *Controller) ReadDB() (*sql.DB, error) {
+	if HasWithLabel[*sql.DB](cont.Deps, DBReplica) {
+		return cont.DBFor(DBReplica)
+	}
+
+	return cont.WriteDB()
+}
@@ -0,0 +1,118 @@
+package loom
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestAppConfig_AddDatabases(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg := &AppConfig{
+		DB: DBConfig{Name: filepath.Join(dir, "app")},
+		Databases: map[string]DBConfig{
+			DBReplica:   {Name: filepath.Join(dir, "app"), ReadOnly: true},
+			"analytics": {Name: filepath.Join(dir, "analytics")},
+		},
+	}
+
+	deps := NewDeps()
+
+	primary, err := cfg.DBConn()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer primary.Close()
+
+	Add(deps, primary)
+
+	if err := cfg.AddDatabases(deps); err != nil {
+		t.Fatalf("AddDatabases() error = %v", err)
+	}
+
+	ctrl := &Controller{Deps: deps}
+
+	write, err := ctrl.WriteDB()
+	if err != nil || write != primary {
+		t.Fatalf("WriteDB() = %v, %v, want the unlabeled database", write, err)
+	}
+
+	read, err := ctrl.ReadDB()
+	if err != nil || read != MustGetWithLabel[*sql.DB](deps, DBReplica) {
+		t.Fatalf("ReadDB() = %v, %v, want the replica", read, err)
+	}
+
+	analytics, err := ctrl.DBFor("analytics")
+	if err != nil || analytics == primary || analytics == read {
+		t.Fatalf("DBFor(analytics) = %v, %v, want the analytics database", analytics, err)
+	}
+
+	if _, err := write.Exec("CREATE TABLE contacts (name TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := write.Exec("INSERT INTO contacts VALUES ('ada')"); err != nil {
+		t.Fatal(err)
+	}
+
+	var name string
+	if err := read.QueryRow("SELECT name FROM contacts").Scan(&name); err != nil || name != "ada" {
+		t.Errorf("replica read = %v, %v, want the row written to the primary", name, err)
+	}
+
+	if _, err := read.Exec("INSERT INTO contacts VALUES ('bob')"); err == nil {
+		t.Error("replica write expected to fail on a read-only database")
+	}
+
+	for _, db := range []*sql.DB{read, analytics} {
+		db.Close()
+	}
+}
+
+func TestController_ReadDB_WithoutReplica(t *testing.T) {
+	primary := &sql.DB{}
+
+	deps := NewDeps()
+	AddWithLabel(deps, primary, DBPrimary)
+
+	ctrl := &Controller{Deps: deps}
+
+	if db, err := ctrl.ReadDB(); err != nil || db != primary {
+		t.Errorf("ReadDB() = %v, %v, want the primary", db, err)
+	}
+
+	if _, err := ctrl.DBFor("analytics"); err == nil {
+		t.Error("DBFor(analytics) expected error for unregistered database")
+	}
+}
+
+func TestAppConfig_Database(t *testing.T) {
+	cfg := &AppConfig{
+		DB:        DBConfig{Name: "app"},
+		Databases: map[string]DBConfig{"analytics": {Name: "events"}},
+	}
+
+	if db, ok := cfg.Database(DBPrimary); !ok || db.Name != "app" {
+		t.Errorf("Database(primary) = %v, %v, want the default database", db, ok)
+	}
+
+	if db, ok := cfg.Database("analytics"); !ok || db.Name != "events" {
+		t.Errorf("Database(analytics) = %v, %v", db, ok)
+	}
+
+	if _, ok := cfg.Database(DBReplica); ok {
+		t.Error("Database(replica) expected undeclared database to be missing")
+	}
+
+	cfg.Host = ":8080"
+	cfg.Databases["replica"] = DBConfig{Host: "replica"}
+
+	err := ValidateConfig(cfg)
+	if err == nil || err.Error() != "invalid config:\n  databases.replica.user: is required when host is set\n  databases.replica.password: is required when host is set\n  databases.replica.name: is required when url is not set" {
+		t.Errorf("ValidateConfig() error = %v", err)
+	}
+}
@@ -3,22 +3,109 @@ package loom
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"sync"
 )
 
 // Deps represents the dependency registry
 type Deps struct {
-	mu       sync.RWMutex
+	*registry
+
+	// resolving is the chain of dependencies being built by factories (see Provide),
+	// factories get a Deps carrying their chain so cycles can be reported in full
+	resolving []depKey
+
+	// scope holds the request-scoped instances when resolving for a request (see Resolve)
+	scope *scope
+
+	// resolved collects the dependencies a factory resolves while it builds, directly
+	// or through other factories, to tell whether a child shadows them (see Child)
+	resolved *[]depKey
+}
+
+// registry holds the dependencies, it is shared by all the views of a Deps
+type registry struct {
+	mu sync.RWMutex
+
+	// services holds the registered instances and *provider factories
 	services map[reflect.Type]map[string]any
+
+	// order records the registrations in the order they were added,
+	// lifecycle hooks run and closers are closed in (reverse) registration order
+	order []depKey
+
+	// registrations describe how each dependency was registered and who consumes it (see Graph)
+	registrations map[depKey]*registration
+
+	// parent is the registry of the Deps a child was created from (see Child),
+	// dependencies not registered in the child are looked up in its parent
+	parent *registry
+
+	// singletons holds the instances a child built from factories of its parents
+	// because it shadows some of their dependencies (see Child)
+	singletons map[*provider]any
+}
+
+// depKey identifies a registered dependency
+type depKey struct {
+	t     reflect.Type
+	label string
+}
+
+func (k depKey) String() string {
+	if k.label == "" {
+		return k.t.String()
+	}
+
+	return fmt.Sprintf("%v[%s]", k.t, k.label)
 }
 
 // NewDeps creates a new dependency registry
 func NewDeps() *Deps {
 	return &Deps{
-		services: make(map[reflect.Type]map[string]any),
+		registry: newRegistry(nil),
 	}
 }
 
+func newRegistry(parent *registry) *registry {
+	return &registry{
+		services:      make(map[reflect.Type]map[string]any),
+		registrations: make(map[depKey]*registration),
+		parent:        parent,
+		singletons:    make(map[*provider]any),
+	}
+}
+
+// Child creates a container inheriting the dependencies of d. Registrations in the child shadow
+// the ones of the parent without changing it, eg. to swap a single service in a test.
+// Factories registered in the parent resolve their dependencies from the child, singletons
+// depending on a shadowed service, directly or not, are built again and kept in the child.
+// Usage: deps := app.Deps.Child(); loom.Add[Clock](deps, fakeClock{})
+func (d *Deps) Child() *Deps {
+	return &Deps{
+		registry: newRegistry(d.registry),
+	}
+}
+
+// lookup finds the registered instance or *provider in the registry or its parents,
+// along with the registry owning it. typeFound reports whether the type is registered at all.
+func (r *registry) lookup(key depKey) (service any, owner *registry, typeFound bool) {
+	for owner = r; owner != nil; owner = owner.parent {
+		owner.mu.RLock()
+		services, exists := owner.services[key.t]
+		service, found := services[key.label]
+		owner.mu.RUnlock()
+
+		typeFound = typeFound || exists
+
+		if found {
+			return service, owner, true
+		}
+	}
+
+	return nil, nil, typeFound
+}
+
 // getType returns the reflect.Type for a generic type T, handling both concrete and interface types
 func getType[T any]() reflect.Type {
 	var zero T
@@ -33,22 +120,44 @@ func getType[T any]() reflect.Type {
 // Add registers a dependency with its type
 // Usage: Add(deps, &MyService{})
 func Add[T any](d *Deps, service T) {
-	AddWithLabel(d, service, "")
+	d.set(getType[T](), "", service, callerSource(1))
 }
 
 // AddWithLabel registers a dependency with a specific label
 // Usage: AddWithLabel(deps, &MyService{}, "primary")
 func AddWithLabel[T any](d *Deps, service T, label string) {
-	d.mu.Lock()
-	defer d.mu.Unlock()
-
 	// For interface types, we want to store under the interface type, not the concrete type
-	serviceType := getType[T]()
+	d.set(getType[T](), label, service, callerSource(1))
+}
+
+// set registers an instance or a *provider, source is where it was registered from
+func (r *registry) set(serviceType reflect.Type, label string, service any, source string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := depKey{t: serviceType, label: label}
+
+	if r.services[serviceType] == nil {
+		r.services[serviceType] = make(map[string]interface{})
+	}
+
+	if _, exists := r.services[serviceType][label]; !exists {
+		r.order = append(r.order, key)
+	}
+
+	r.services[serviceType][label] = service
+
+	reg := &registration{lifetime: Singleton, source: source}
+	if p, ok := service.(*provider); ok {
+		reg.lifetime, reg.factory = p.lifetime, true
+	}
 
-	if d.services[serviceType] == nil {
-		d.services[serviceType] = make(map[string]interface{})
+	// consumers are kept, they still depend on the type when it is replaced
+	if existing, ok := r.registrations[key]; ok {
+		reg.consumers = existing.consumers
 	}
-	d.services[serviceType][label] = service
+
+	r.registrations[key] = reg
 }
 
 // Get retrieves a dependency by type
@@ -60,20 +169,12 @@ func Get[T any](d *Deps) (T, error) {
 // GetWithLabel retrieves a dependency by type and label
 // Usage: service, err := GetWithLabel[MyService](deps, "primary")
 func GetWithLabel[T any](d *Deps, label string) (T, error) {
-	d.mu.RLock()
-	defer d.mu.RUnlock()
-
 	var zero T
 	serviceType := getType[T]()
 
-	services, exists := d.services[serviceType]
-	if !exists {
-		return zero, fmt.Errorf("no service registered for type %v", serviceType)
-	}
-
-	service, exists := services[label]
-	if !exists {
-		return zero, fmt.Errorf("no service registered for type %v with label '%s'", serviceType, label)
+	service, err := d.get(serviceType, label)
+	if err != nil {
+		return zero, err
 	}
 
 	// Type assertion
@@ -84,6 +185,82 @@ func GetWithLabel[T any](d *Deps, label string) (T, error) {
 	return zero, fmt.Errorf("type assertion failed for type %v", serviceType)
 }
 
+// get retrieves a dependency by its reflect.Type and label, building it if it is provided by a factory
+func (d *Deps) get(serviceType reflect.Type, label string) (any, error) {
+	key := depKey{t: serviceType, label: label}
+
+	service, owner, typeFound := d.lookup(key)
+	if owner == nil {
+		if !typeFound {
+			return nil, fmt.Errorf("no service registered for type %v", serviceType)
+		}
+
+		return nil, fmt.Errorf("no service registered for type %v with label '%s'", serviceType, label)
+	}
+
+	// dependencies resolved by a factory are consumed by the dependency it builds
+	if len(d.resolving) > 0 {
+		owner.consume(key, d.resolving[len(d.resolving)-1].String())
+	}
+
+	d.record(key)
+
+	if p, ok := service.(*provider); ok {
+		return p.resolve(d, owner, key)
+	}
+
+	return service, nil
+}
+
+// record adds keys to the dependencies collected for the factory being built
+func (d *Deps) record(keys ...depKey) {
+	if d.resolved != nil {
+		*d.resolved = append(*d.resolved, keys...)
+	}
+}
+
+// shadows reports whether d resolves one of keys to another registration than owner does,
+// because d is a child of owner registering it
+func (d *Deps) shadows(owner *registry, keys []depKey) bool {
+	if d.registry == owner {
+		return false
+	}
+
+	for _, key := range keys {
+		_, from, _ := d.lookup(key)
+		_, want, _ := owner.lookup(key)
+
+		if from != want {
+			return true
+		}
+	}
+
+	return false
+}
+
+// singleton returns the instance r built from a factory of its parents (see Child)
+func (r *registry) singleton(p *provider) (any, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	value, ok := r.singletons[p]
+
+	return value, ok
+}
+
+func (r *registry) setSingleton(p *provider, value any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.singletons[p] = value
+}
+
+// has checks if a dependency with the reflect.Type and label is registered
+func (d *Deps) has(serviceType reflect.Type, label string) bool {
+	_, owner, _ := d.lookup(depKey{t: serviceType, label: label})
+	return owner != nil
+}
+
 // MustGet retrieves a dependency by type, panicking if not found
 // Usage: service := MustGet[MyService](deps)
 func MustGet[T any](d *Deps) T {
@@ -113,18 +290,7 @@ func Has[T any](d *Deps) bool {
 // HasWithLabel checks if a dependency of the given type with the specified label is registered
 // Usage: if HasWithLabel[MyService](deps, "primary") { ... }
 func HasWithLabel[T any](d *Deps, label string) bool {
-	d.mu.RLock()
-	defer d.mu.RUnlock()
-
-	serviceType := getType[T]()
-
-	services, exists := d.services[serviceType]
-	if !exists {
-		return false
-	}
-
-	_, exists = services[label]
-	return exists
+	return d.has(getType[T](), label)
 }
 
 // Remove removes a dependency by type
@@ -136,34 +302,39 @@ func Remove[T any](d *Deps) {
 // RemoveWithLabel removes a dependency by type and label
 // Usage: RemoveWithLabel[MyService](deps, "primary")
 func RemoveWithLabel[T any](d *Deps, label string) {
-	d.mu.Lock()
-	defer d.mu.Unlock()
+	d.remove(depKey{t: getType[T](), label: label})
+}
 
-	serviceType := getType[T]()
+func (r *registry) remove(key depKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	if services, exists := d.services[serviceType]; exists {
-		delete(services, label)
+	if services, exists := r.services[key.t]; exists {
+		delete(services, key.label)
 		if len(services) == 0 {
-			delete(d.services, serviceType)
+			delete(r.services, key.t)
 		}
 	}
+
+	r.order = slices.DeleteFunc(r.order, func(k depKey) bool {
+		return k == key
+	})
+
+	delete(r.registrations, key)
 }
 
 // GetAll returns all registered dependencies of a given type
 // Usage: services := GetAll[MyService](deps)
 func GetAll[T any](d *Deps) map[string]T {
-	d.mu.RLock()
-	defer d.mu.RUnlock()
-
 	serviceType := getType[T]()
 
-	services, exists := d.services[serviceType]
-	if !exists {
-		return make(map[string]T)
-	}
-
 	result := make(map[string]T)
-	for label, service := range services {
+	for _, label := range d.labels(serviceType) {
+		service, err := d.get(serviceType, label)
+		if err != nil {
+			continue
+		}
+
 		if typedService, ok := service.(T); ok {
 			result[label] = typedService
 		}
@@ -175,17 +346,24 @@ func GetAll[T any](d *Deps) map[string]T {
 // Count returns the number of registered dependencies of a given type
 // Usage: count := Count[MyService](deps)
 func Count[T any](d *Deps) int {
-	d.mu.RLock()
-	defer d.mu.RUnlock()
+	return len(d.labels(getType[T]()))
+}
 
-	serviceType := getType[T]()
+// labels returns the labels a type is registered with in the registry and its parents
+func (r *registry) labels(serviceType reflect.Type) []string {
+	var labels []string
 
-	services, exists := d.services[serviceType]
-	if !exists {
-		return 0
+	for reg := r; reg != nil; reg = reg.parent {
+		reg.mu.RLock()
+		for label := range reg.services[serviceType] {
+			if !slices.Contains(labels, label) {
+				labels = append(labels, label)
+			}
+		}
+		reg.mu.RUnlock()
 	}
 
-	return len(services)
+	return labels
 }
 
 // Clear removes all registered dependencies
@@ -193,16 +371,63 @@ func (d *Deps) Clear() {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 	d.services = make(map[reflect.Type]map[string]any)
+	d.order = nil
+	d.registrations = make(map[depKey]*registration)
+	d.singletons = make(map[*provider]any)
 }
 
-// GetRegisteredTypes returns all registered types
-func (d *Deps) GetRegisteredTypes() []reflect.Type {
+// ordered returns the registered dependencies in registration order, factories are
+// only included once they built their singleton. A pointer registered under several
+// types or labels is only returned once.
+func (d *Deps) ordered() []any {
 	d.mu.RLock()
-	defer d.mu.RUnlock()
 
-	types := make([]reflect.Type, 0, len(d.services))
-	for t := range d.services {
-		types = append(types, t)
+	registered := make([]any, 0, len(d.order))
+	for _, k := range d.order {
+		registered = append(registered, d.services[k.t][k.label])
 	}
+
+	d.mu.RUnlock()
+
+	var (
+		services []any
+		seen     = make(map[any]bool)
+	)
+
+	for _, service := range registered {
+		if p, ok := service.(*provider); ok {
+			if service, ok = p.singleton(); !ok {
+				continue
+			}
+		}
+
+		if service != nil && reflect.TypeOf(service).Kind() == reflect.Pointer {
+			if seen[service] {
+				continue
+			}
+
+			seen[service] = true
+		}
+
+		services = append(services, service)
+	}
+
+	return services
+}
+
+// GetRegisteredTypes returns all registered types
+func (d *Deps) GetRegisteredTypes() []reflect.Type {
+	var types []reflect.Type
+
+	for reg := d.registry; reg != nil; reg = reg.parent {
+		reg.mu.RLock()
+		for t := range reg.services {
+			if !slices.Contains(types, t) {
+				types = append(types, t)
+			}
+		}
+		reg.mu.RUnlock()
+	}
+
 	return types
 }
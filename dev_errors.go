@@ -0,0 +1,278 @@
+package loom
+
+import (
+	"bufio"
+	"errors"
+	"html/template"
+	"net/http"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// snippetContext is the number of source lines shown around the failing line
+const snippetContext = 5
+
+// frameworkFuncPrefixes are skipped when looking for the frame that caused the error
+var frameworkFuncPrefixes = []string{
+	"runtime.",
+	"reflect.",
+	"github.com/aneshas/loom.",
+	"github.com/labstack/echo/",
+}
+
+type stackFrame struct {
+	Function string
+	File     string
+	Line     int
+	App      bool
+}
+
+type sourceLine struct {
+	Number  int
+	Text    string
+	Failing bool
+}
+
+type devErrorPage struct {
+	Code    int
+	Message string
+	Error   string
+	Method  string
+	URL     string
+	Headers [][2]string
+	Route   *RouteInfo
+	Frames  []stackFrame
+	Source  *devErrorSource
+}
+
+type devErrorSource struct {
+	File  string
+	Lines []sourceLine
+}
+
+// renderDevErrorPage renders the page shown instead of the error pages when Loom.Debug is set
+func (l *Loom) renderDevErrorPage(c echo.Context, err error, httpErr *HTTPError) error {
+	req := c.Request()
+
+	page := devErrorPage{
+		Code:    httpErr.Code,
+		Message: httpErr.Message,
+		Error:   err.Error(),
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Route:   l.routeFor(req.Method, c.Path()),
+		Frames:  stackFrames(errorStack(err)),
+	}
+
+	for name, values := range req.Header {
+		page.Headers = append(page.Headers, [2]string{name, strings.Join(values, ", ")})
+	}
+
+	sort.Slice(page.Headers, func(i, j int) bool { return page.Headers[i][0] < page.Headers[j][0] })
+
+	file, line := failingLine(page.Frames)
+	if file == "" && page.Route != nil {
+		file, line = l.actionLocation(*page.Route)
+	}
+
+	if file != "" {
+		page.Source = readSource(file, line)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
+	c.Response().WriteHeader(httpErr.Code)
+
+	return devErrorTemplate.Execute(c.Response(), page)
+}
+
+// routeFor returns the Loom route matched by the request, if any
+func (l *Loom) routeFor(method, path string) *RouteInfo {
+	for _, route := range l.routes {
+		if route.Path == path && route.Method == method {
+			return &route
+		}
+	}
+
+	return nil
+}
+
+// actionLocation returns the source location of the controller action handling the route
+func (l *Loom) actionLocation(route RouteInfo) (string, int) {
+	for _, ctrl := range l.controllerRegistry {
+		if ctrl.Type.String() != route.Controller {
+			continue
+		}
+
+		method, ok := ctrl.Type.MethodByName(route.Action)
+		if !ok {
+			return "", 0
+		}
+
+		fn := runtime.FuncForPC(method.Func.Pointer())
+		if fn == nil {
+			return "", 0
+		}
+
+		return fn.FileLine(fn.Entry())
+	}
+
+	return "", 0
+}
+
+// errorStack returns the stack captured by a recovered panic or NewHTTPError
+func errorStack(err error) []uintptr {
+	var panicErr *panicError
+	if errors.As(err, &panicErr) {
+		return panicErr.stack
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.stack
+	}
+
+	return nil
+}
+
+func stackFrames(stack []uintptr) []stackFrame {
+	if len(stack) == 0 {
+		return nil
+	}
+
+	var frames []stackFrame
+
+	callersFrames := runtime.CallersFrames(stack)
+	for {
+		frame, more := callersFrames.Next()
+
+		frames = append(frames, stackFrame{
+			Function: frame.Function,
+			File:     frame.File,
+			Line:     frame.Line,
+			App:      !isFrameworkFunc(frame.Function),
+		})
+
+		if !more {
+			break
+		}
+	}
+
+	return frames
+}
+
+func isFrameworkFunc(name string) bool {
+	for _, prefix := range frameworkFuncPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// failingLine returns the location of the first application frame
+func failingLine(frames []stackFrame) (string, int) {
+	for _, frame := range frames {
+		if frame.App {
+			return frame.File, frame.Line
+		}
+	}
+
+	return "", 0
+}
+
+func readSource(file string, line int) *devErrorSource {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+
+	defer f.Close()
+
+	source := &devErrorSource{File: file}
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		if n < line-snippetContext {
+			continue
+		}
+
+		if n > line+snippetContext {
+			break
+		}
+
+		source.Lines = append(source.Lines, sourceLine{
+			Number:  n,
+			Text:    scanner.Text(),
+			Failing: n == line,
+		})
+	}
+
+	return source
+}
+
+var devErrorTemplate = template.Must(template.New("error").Funcs(template.FuncMap{
+	"statusText": http.StatusText,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Code}} {{statusText .Code}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 0; color: #212529; background: #f8f9fa; }
+header { background: #b02a37; color: #fff; padding: 1.5rem 2rem; }
+header h1 { margin: 0 0 .5rem; font-size: 1.5rem; }
+header pre { margin: 0; white-space: pre-wrap; }
+section { margin: 1.5rem 2rem; }
+h2 { font-size: 1.1rem; border-bottom: 1px solid #dee2e6; padding-bottom: .25rem; }
+pre, code, td { font-family: ui-monospace, monospace; font-size: .85rem; }
+table { border-collapse: collapse; }
+td { padding: .15rem 1rem .15rem 0; vertical-align: top; }
+.source { background: #fff; border: 1px solid #dee2e6; padding: .5rem 0; overflow-x: auto; }
+.source div { padding: 0 1rem; white-space: pre; }
+.source .failing { background: #f8d7da; }
+.source span { color: #6c757d; display: inline-block; width: 3rem; }
+.framework { color: #6c757d; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Code}} {{statusText .Code}}: {{.Message}}</h1>
+<pre>{{.Error}}</pre>
+</header>
+{{with .Source}}
+<section>
+<h2>Source</h2>
+<p><code>{{.File}}</code></p>
+<div class="source">{{range .Lines}}<div{{if .Failing}} class="failing"{{end}}><span>{{.Number}}</span>{{.Text}}</div>{{end}}</div>
+</section>
+{{end}}
+<section>
+<h2>Request</h2>
+<p><code>{{.Method}} {{.URL}}</code></p>
+<table>{{range .Headers}}<tr><td>{{index . 0}}</td><td>{{index . 1}}</td></tr>{{end}}</table>
+</section>
+{{with .Route}}
+<section>
+<h2>Route</h2>
+<table>
+<tr><td>Route</td><td>{{.Method}} {{.Path}}</td></tr>
+<tr><td>Name</td><td>{{.Name}}</td></tr>
+<tr><td>Action</td><td>{{.Controller}}.{{.Action}}</td></tr>
+<tr><td>Middleware</td><td>{{range $i, $m := .Middleware}}{{if $i}}, {{end}}{{$m}}{{end}}</td></tr>
+</table>
+</section>
+{{end}}
+{{with .Frames}}
+<section>
+<h2>Stack trace</h2>
+<table>{{range .}}<tr{{if not .App}} class="framework"{{end}}><td>{{.Function}}<br>{{.File}}:{{.Line}}</td></tr>{{end}}</table>
+</section>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,19 @@
+package loom
+
+import "os"
+
+// EnvVar is the environment variable selecting the environment the application runs in
+const EnvVar = "LOOM_ENV"
+
+// DefaultEnv is the environment used when LOOM_ENV is not set
+const DefaultEnv = "dev"
+
+// Env returns the environment the application runs in, eg. "dev" or "prod".
+// It is read from LOOM_ENV, which the loom CLI sets from its --env flag.
+func Env() string {
+	if env := os.Getenv(EnvVar); env != "" {
+		return env
+	}
+
+	return DefaultEnv
+}
@@ -0,0 +1,197 @@
+package loom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+
+	"github.com/a-h/templ"
+	"github.com/labstack/echo/v4"
+)
+
+// HTTPError is an error carrying the status code of the response and a message
+// that is safe to show to users. The wrapped error is only shown on the dev error page.
+// Usage: return loom.NewHTTPError(http.StatusForbidden, "You can't edit this contact")
+type HTTPError struct {
+	Code    int
+	Message string
+	Err     error
+
+	stack []uintptr
+}
+
+// NewHTTPError creates an HTTPError, the message defaults to the status text of code
+func NewHTTPError(code int, message ...string) *HTTPError {
+	e := &HTTPError{
+		Code:    code,
+		Message: http.StatusText(code),
+		stack:   callers(3),
+	}
+
+	if len(message) > 0 {
+		e.Message = message[0]
+	}
+
+	return e
+}
+
+// Wrap sets the underlying error
+func (e *HTTPError) Wrap(err error) *HTTPError {
+	e.Err = err
+	return e
+}
+
+func (e *HTTPError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("code=%d, message=%s, err=%v", e.Code, e.Message, e.Err)
+	}
+
+	return fmt.Sprintf("code=%d, message=%s", e.Code, e.Message)
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorKey is the request context key holding the HTTPError an error page is rendered for
+type ErrorKey struct{}
+
+// ErrorFrom returns the error an error page is being rendered for
+func ErrorFrom(ctx context.Context) (*HTTPError, bool) {
+	e, ok := ctx.Value(ErrorKey{}).(*HTTPError)
+	return e, ok
+}
+
+// panicError is a recovered panic along with the stack it was raised from
+type panicError struct {
+	value any
+	stack []uintptr
+}
+
+func (e *panicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.value)
+}
+
+func (e *panicError) Unwrap() error {
+	err, _ := e.value.(error)
+	return err
+}
+
+// recoverMiddleware turns panics into errors handled by Loom's error handler
+func recoverMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) (err error) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			if r == http.ErrAbortHandler {
+				panic(r)
+			}
+
+			err = &panicError{value: r, stack: callers(4)}
+		}()
+
+		return next(c)
+	}
+}
+
+func callers(skip int) []uintptr {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(skip, pcs)
+
+	return pcs[:n]
+}
+
+// toHTTPError converts any error returned by a handler to an HTTPError,
+// errors other than HTTPError and echo.HTTPError become internal server errors
+func toHTTPError(err error) *HTTPError {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr
+	}
+
+	var echoErr *echo.HTTPError
+	if errors.As(err, &echoErr) {
+		return &HTTPError{
+			Code:    echoErr.Code,
+			Message: fmt.Sprint(echoErr.Message),
+			Err:     echoErr.Internal,
+		}
+	}
+
+	return &HTTPError{
+		Code:    http.StatusInternalServerError,
+		Message: http.StatusText(http.StatusInternalServerError),
+		Err:     err,
+	}
+}
+
+// handleError is Loom's echo.HTTPErrorHandler. Clients asking for JSON get echo's default
+// error response. In debug mode it renders the dev error page, otherwise the matching
+// ErrorPages component or echo's default error response.
+func (l *Loom) handleError(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	httpErr := toHTTPError(err)
+
+	if httpErr.Code >= http.StatusInternalServerError {
+		c.Logger().Error(err)
+	}
+
+	if c.Request().Method == http.MethodHead {
+		if err := c.NoContent(httpErr.Code); err != nil {
+			c.Logger().Error(err)
+		}
+
+		return
+	}
+
+	if requestedFormat(c) == FormatJSON {
+		l.E.DefaultHTTPErrorHandler(echo.NewHTTPError(httpErr.Code, httpErr.Message), c)
+		return
+	}
+
+	if l.Debug {
+		if err := l.renderDevErrorPage(c, err, httpErr); err != nil {
+			c.Logger().Error(err)
+		}
+
+		return
+	}
+
+	page := l.errorPage(httpErr.Code)
+	if page == nil {
+		l.E.DefaultHTTPErrorHandler(echo.NewHTTPError(httpErr.Code, httpErr.Message), c)
+		return
+	}
+
+	ctx := context.WithValue(c.Request().Context(), ErrorKey{}, httpErr)
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
+	c.Response().WriteHeader(httpErr.Code)
+
+	if err := l.Renderer(c, page); err != nil {
+		c.Logger().Error(err)
+	}
+}
+
+// errorPage returns the page for the status code, server errors without a page of
+// their own use the 500 page
+func (l *Loom) errorPage(code int) templ.Component {
+	if page, ok := l.ErrorPages[code]; ok {
+		return page
+	}
+
+	if code >= http.StatusInternalServerError {
+		return l.ErrorPages[http.StatusInternalServerError]
+	}
+
+	return nil
+}
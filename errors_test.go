@@ -0,0 +1,155 @@
+package loom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/labstack/echo/v4"
+)
+
+type failingController struct {
+	Controller
+}
+
+func (fc *failingController) Index(c echo.Context) error {
+	panic("something broke")
+}
+
+func (fc *failingController) Show(c echo.Context) error {
+	return NewHTTPError(http.StatusForbidden, "You can't see this")
+}
+
+func (fc *failingController) Edit(c echo.Context) error {
+	return errors.New("database is down")
+}
+
+func (fc *failingController) Update(c echo.Context) error {
+	return NewHTTPError(http.StatusServiceUnavailable).Wrap(errors.New("maintenance"))
+}
+
+func errorPage(name string) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		err, ok := ErrorFrom(ctx)
+		if !ok {
+			return errors.New("no error in context")
+		}
+
+		_, werr := fmt.Fprintf(w, "%s: %d %s", name, err.Code, err.Message)
+
+		return werr
+	})
+}
+
+func newFailingApp(debug bool) *Loom {
+	l := New(NewDeps())
+
+	l.Debug = debug
+	l.ErrorPages = map[int]templ.Component{
+		http.StatusNotFound:            errorPage("not found"),
+		http.StatusInternalServerError: errorPage("server error"),
+	}
+
+	Register[*failingController](l)
+
+	l.GET("/panic", "failing.index")
+	l.GET("/forbidden", "failing.show")
+	l.GET("/error", "failing.edit")
+	l.GET("/unavailable", "failing.update")
+
+	return l
+}
+
+func TestErrorPages(t *testing.T) {
+	l := newFailingApp(false)
+
+	tests := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/panic", http.StatusInternalServerError, "server error: 500 Internal Server Error"},
+		{"/error", http.StatusInternalServerError, "server error: 500 Internal Server Error"},
+		{"/unavailable", http.StatusServiceUnavailable, "server error: 503 Service Unavailable"},
+		{"/missing", http.StatusNotFound, "not found: 404 Not Found"},
+		{"/forbidden", http.StatusForbidden, `{"message":"You can't see this"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := serve(l, http.MethodGet, tt.path)
+
+			if rec.Code != tt.code {
+				t.Errorf("status = %v, want %v", rec.Code, tt.code)
+			}
+
+			if body := strings.TrimSpace(rec.Body.String()); body != tt.body {
+				t.Errorf("body = %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
+
+func TestErrorPages_InternalErrorsAreNotShown(t *testing.T) {
+	l := newFailingApp(false)
+	l.ErrorPages = nil
+
+	rec := serve(l, http.MethodGet, "/error")
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %v, want %v", rec.Code, http.StatusInternalServerError)
+	}
+
+	if strings.Contains(rec.Body.String(), "database is down") {
+		t.Errorf("body leaks the internal error: %s", rec.Body.String())
+	}
+}
+
+func TestDevErrorPage(t *testing.T) {
+	l := newFailingApp(true)
+
+	tests := []struct {
+		path string
+		code int
+		want []string
+	}{
+		{"/panic", http.StatusInternalServerError, []string{"panic: something broke", "Stack trace", "failing.index", "errors_test.go"}},
+		{"/forbidden", http.StatusForbidden, []string{"You can&#39;t see this", "Stack trace", "failing.show"}},
+		{"/error", http.StatusInternalServerError, []string{"database is down", "*loom.failingController.Edit", "Source"}},
+		{"/missing", http.StatusNotFound, []string{"GET /missing"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := serve(l, http.MethodGet, tt.path)
+
+			if rec.Code != tt.code {
+				t.Errorf("status = %v, want %v", rec.Code, tt.code)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(rec.Body.String(), want) {
+					t.Errorf("dev error page does not contain %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPError(t *testing.T) {
+	cause := errors.New("not allowed")
+	err := NewHTTPError(http.StatusForbidden).Wrap(cause)
+
+	if err.Message != "Forbidden" {
+		t.Errorf("Message = %v, want default status text", err.Message)
+	}
+
+	if !errors.Is(err, cause) {
+		t.Error("HTTPError does not unwrap to its cause")
+	}
+}
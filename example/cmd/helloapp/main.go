@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"log"
 
 	"github.com/aneshas/helloapp/config"
@@ -14,21 +16,36 @@ import (
 func main() {
 	cfg := loom.MustLoadConfig[config.Config]("./config")
 
-	conn, err := cfg.DBConn()
-	check(err)
-
 	deps := loom.NewDeps()
 
-	loom.Add(deps, conn)
+	loom.Provide(deps, func(d *loom.Deps) (*sql.DB, error) {
+		return cfg.DBConn()
+	})
+
+	check(cfg.AddDatabases(deps))
+
+	// controllers read live settings through the holder, it is reloaded on config changes in dev
+	holder := loom.NewConfigHolder(cfg)
+
+	loom.Add(deps, holder)
 
 	l := loom.New(deps)
 
+	l.Debug = cfg.Debug
+
+	if cfg.Debug {
+		l.OnStart(func(ctx context.Context) error {
+			go loom.WatchConfig(ctx, holder, "./config", 0)
+			return nil
+		})
+	}
+
 	controller.Register(l)
 
-	web.ConfigureServer(l)
+	web.ConfigureServer(l, cfg)
 	web.ConfigureRoutes(l)
 
-	check(l.Start(cfg.Host))
+	check(l.Run(context.Background(), cfg.Host))
 }
 
 func check(err error) {
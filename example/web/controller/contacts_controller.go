@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aneshas/helloapp/web/routes"
 	"github.com/aneshas/helloapp/web/views"
 	"github.com/aneshas/helloapp/web/views/contacts"
 	"github.com/aneshas/loom"
@@ -14,13 +15,7 @@ import (
 type ContactsController struct {
 	loom.Controller
 
-	db *sql.DB
-}
-
-func (ctrl *ContactsController) Init() error {
-	ctrl.db = loom.MustGet[*sql.DB](ctrl.Deps)
-
-	return nil
+	DB *sql.DB `loom:"inject"`
 }
 
 func (ctrl *ContactsController) New(c echo.Context) error {
@@ -29,7 +24,7 @@ func (ctrl *ContactsController) New(c echo.Context) error {
 	return views.Render(c, contacts.Form(m))
 }
 
-func (ctrl *ContactsController) Post(c echo.Context) error {
+func (ctrl *ContactsController) Create(c echo.Context) error {
 	var contact Contact
 
 	m, err := ctrl.BindForm(c, &contact)
@@ -39,12 +34,12 @@ func (ctrl *ContactsController) Post(c echo.Context) error {
 
 	if m.HasErrors() {
 		loom.FlashErrorNow(c, "Please fix form errors and re-submit.")
-		return views.Render(c, contacts.Form(m))
+		return loom.RespondWithStatus(c, http.StatusUnprocessableEntity, loom.HTML(contacts.Form(m)), loom.JSON(m))
 	}
 
 	ctx := c.Request().Context()
 
-	err = contact.ToDB().Insert(ctx, ctrl.db, boil.Infer())
+	err = contact.ToDB().Insert(ctx, ctrl.DB, boil.Infer())
 	if err != nil {
 		loom.FlashErrorNow(c, err.Error()) // generic message but log the error for debugging
 		return views.Render(c, contacts.Form(m))
@@ -52,5 +47,5 @@ func (ctrl *ContactsController) Post(c echo.Context) error {
 
 	loom.FlashSuccess(c, "Contact saved successfully!")
 
-	return c.Redirect(http.StatusFound, "/contacts")
+	return c.Redirect(http.StatusFound, routes.ContactsNew())
 }
@@ -1,8 +0,0 @@
-package controller
-
-import "github.com/aneshas/loom"
-
-func Register(l *loom.Loom) {
-	loom.Register[*PagesController](l)
-	loom.Register[*ContactsController](l)
-}
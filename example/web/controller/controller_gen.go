@@ -0,0 +1,16 @@
+// Code generated by loom gen controllers. DO NOT EDIT.
+
+package controller
+
+import "github.com/aneshas/loom"
+
+// Register registers the controllers with reflection-free bindings for their actions
+func Register(l *loom.Loom) {
+	loom.Register[*ContactsController](l)
+	loom.Bind(l, map[string]loom.ActionFunc[*ContactsController]{
+		"Create": (*ContactsController).Create,
+		"New":    (*ContactsController).New,
+	})
+
+	loom.Register[*PagesController](l)
+}
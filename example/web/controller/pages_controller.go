@@ -1,20 +1,17 @@
 package controller
 
 import (
-	"github.com/aneshas/helloapp/web/views"
+	"context"
+
+	"github.com/a-h/templ"
 	"github.com/aneshas/helloapp/web/views/pages"
 	"github.com/aneshas/loom"
-	"github.com/labstack/echo/v4"
 )
 
 type PagesController struct {
 	loom.Controller
 }
 
-func (ctrl *PagesController) Home(c echo.Context) error {
-	return views.Render(c, pages.Home())
-}
-
-func (ctrl *PagesController) NotFound(c echo.Context) error {
-	return views.Render(c, pages.NotFound())
+func (ctrl *PagesController) Home(ctx context.Context) (templ.Component, error) {
+	return pages.Home(), nil
 }
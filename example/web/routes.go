@@ -8,8 +8,5 @@ import (
 func ConfigureRoutes(l *loom.Loom) {
 	l.GET("/", "pages.home")
 
-	l.GET("/contacts/new", "contacts.new")
-	l.POST("/contacts/new", "contacts.post")
-
-	l.GET("*", "pages.not_found")
+	l.Resources("/contacts", "contacts")
 }
@@ -0,0 +1,27 @@
+// Code generated by loom gen routes. DO NOT EDIT.
+
+package routes
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// PagesHome returns the path of the pages.home route: GET /
+func PagesHome() string {
+	return "/"
+}
+
+// ContactsNew returns the path of the contacts.new route: GET /contacts/new
+func ContactsNew() string {
+	return "/contacts/new"
+}
+
+// ContactsCreate returns the path of the contacts.create route: POST /contacts
+func ContactsCreate() string {
+	return "/contacts"
+}
+
+func param(v any) string {
+	return url.PathEscape(fmt.Sprint(v))
+}
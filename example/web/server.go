@@ -3,6 +3,10 @@ package web
 import (
 	"net/http"
 
+	"github.com/a-h/templ"
+	"github.com/aneshas/helloapp/config"
+	"github.com/aneshas/helloapp/web/views"
+	"github.com/aneshas/helloapp/web/views/pages"
 	"github.com/aneshas/loom"
 	"github.com/arl/statsviz"
 	"github.com/labstack/echo/v4"
@@ -10,15 +14,25 @@ import (
 )
 
 // ConfigureServer is where we configure the echo server and global middleware
-func ConfigureServer(g *loom.Loom) {
+func ConfigureServer(g *loom.Loom, cfg *config.Config) {
 	g.E.HideBanner = true
 
+	g.Renderer = views.Render
+
+	g.ErrorPages = map[int]templ.Component{
+		http.StatusNotFound:            pages.NotFound(),
+		http.StatusUnprocessableEntity: pages.Unprocessable(),
+		http.StatusInternalServerError: pages.ServerError(),
+	}
+
+	g.E.Pre(loom.MethodOverrideMiddleware, g.FormatMiddleware)
+
 	g.E.Use(
 		middleware.CSRFWithConfig(middleware.CSRFConfig{
 			TokenLookup: "form:_csrf",
 		}),
 		loom.CSRFMiddleware,
-		loom.FlashMiddleware,
+		loom.FlashMiddlewareWithConfig(cfg.Flash),
 	)
 
 	g.E.Static("/assets", "web/views/assets")
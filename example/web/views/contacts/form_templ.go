@@ -8,6 +8,7 @@ package contacts
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
+import "github.com/aneshas/helloapp/web/routes"
 import "github.com/aneshas/helloapp/web/views/components"
 import "github.com/aneshas/loom"
 
@@ -107,7 +108,7 @@ func Form(m loom.ViewModel) templ.Component {
 			}
 			return nil
 		})
-		templ_7745c5c3_Err = components.Form(m, "form-example", "/contacts/new").Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = components.Form(m, "form-example", "POST", routes.ContactsCreate()).Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -18,6 +18,10 @@ func Render(c echo.Context, component templ.Component, opts ...loom.RenderOption
 
 	if options.Layout == nil {
 		options.Layout = layouts.App
+
+		if layout, ok := loom.LayoutFrom(c.Request().Context()); ok {
+			options.Layout = layout
+		}
 	}
 
 	return layouts.
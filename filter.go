@@ -0,0 +1,103 @@
+package loom
+
+import "github.com/labstack/echo/v4"
+
+// FilterFunc is a controller filter, a non-nil error short-circuits the action
+type FilterFunc func(c echo.Context) error
+
+// Filter runs a FilterFunc before or after a set of controller actions.
+// Controllers declare them with a Filters method:
+//
+//	func (ctrl *ContactsController) Filters() []loom.Filter {
+//		return []loom.Filter{
+//			loom.Before(ctrl.loadContact, loom.Only("show", "edit", "update")),
+//			loom.After(ctrl.audit, loom.Except("index")),
+//		}
+//	}
+type Filter struct {
+	after   bool
+	fn      FilterFunc
+	actions *actionSet
+}
+
+// Before creates a filter that runs before the actions, optionally narrowed with Only or Except
+func Before(fn FilterFunc, opts ...ActionOption) Filter {
+	return Filter{fn: fn, actions: newActionSet(opts...)}
+}
+
+// After creates a filter that runs after the actions succeed, optionally narrowed with Only or Except
+func After(fn FilterFunc, opts ...ActionOption) Filter {
+	return Filter{after: true, fn: fn, actions: newActionSet(opts...)}
+}
+
+// BeforeActioner is implemented by controllers that run a filter before every action
+type BeforeActioner interface {
+	BeforeAction(c echo.Context) error
+}
+
+// AfterActioner is implemented by controllers that run a filter after every successful action
+type AfterActioner interface {
+	AfterAction(c echo.Context) error
+}
+
+// Filterer is implemented by controllers declaring per-action filters
+type Filterer interface {
+	Filters() []Filter
+}
+
+// actionFilters are the filters that apply to a single controller action
+type actionFilters struct {
+	before []FilterFunc
+	after  []FilterFunc
+}
+
+// filtersFor collects the filters of the controller instance that apply to action ("edit").
+// BeforeAction runs first and AfterAction last, declared filters run in declaration order.
+func filtersFor(instance any, action string) actionFilters {
+	var f actionFilters
+
+	if ctrl, ok := instance.(BeforeActioner); ok {
+		f.before = append(f.before, ctrl.BeforeAction)
+	}
+
+	if ctrl, ok := instance.(Filterer); ok {
+		for _, filter := range ctrl.Filters() {
+			if !filter.actions.includes(action) {
+				continue
+			}
+
+			if filter.after {
+				f.after = append(f.after, filter.fn)
+			} else {
+				f.before = append(f.before, filter.fn)
+			}
+		}
+	}
+
+	if ctrl, ok := instance.(AfterActioner); ok {
+		f.after = append(f.after, ctrl.AfterAction)
+	}
+
+	return f
+}
+
+// run calls the before filters, the action and the after filters, stopping at the first error
+func (f actionFilters) run(c echo.Context, action func() error) error {
+	for _, filter := range f.before {
+		if err := filter(c); err != nil {
+			return err
+		}
+	}
+
+	if err := action(); err != nil {
+		return err
+	}
+
+	for _, filter := range f.after {
+		if err := filter(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
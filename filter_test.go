@@ -0,0 +1,116 @@
+package loom
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+type filteredController struct {
+	Controller
+}
+
+func trace(c echo.Context, step string) {
+	steps, _ := c.Get("trace").([]string)
+	c.Set("trace", append(steps, step))
+}
+
+func (fc *filteredController) BeforeAction(c echo.Context) error {
+	trace(c, "before_action")
+
+	if c.QueryParam("deny") != "" {
+		return echo.NewHTTPError(http.StatusForbidden, "denied")
+	}
+
+	return nil
+}
+
+func (fc *filteredController) AfterAction(c echo.Context) error {
+	trace(c, "after_action")
+
+	steps, _ := c.Get("trace").([]string)
+	c.Response().Header().Set("X-Trace", strings.Join(steps, ","))
+
+	return nil
+}
+
+func (fc *filteredController) Filters() []Filter {
+	return []Filter{
+		Before(fc.load, Only("edit", "update")),
+		Before(fc.authorize, Except("index")),
+		After(fc.audit, Only("update")),
+	}
+}
+
+func (fc *filteredController) load(c echo.Context) error {
+	trace(c, "load")
+	return nil
+}
+
+func (fc *filteredController) authorize(c echo.Context) error {
+	trace(c, "authorize")
+	return nil
+}
+
+func (fc *filteredController) audit(c echo.Context) error {
+	trace(c, "audit")
+	return nil
+}
+
+func (fc *filteredController) Index(c echo.Context) error {
+	trace(c, "index")
+	return nil
+}
+
+func (fc *filteredController) Edit(c echo.Context) error {
+	trace(c, "edit")
+	return nil
+}
+
+func (fc *filteredController) Update(c echo.Context) error {
+	trace(c, "update")
+
+	if c.QueryParam("fail") != "" {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, "invalid")
+	}
+
+	return nil
+}
+
+func TestFilters(t *testing.T) {
+	loom := New(NewDeps())
+
+	Register[*filteredController](loom)
+
+	loom.Resources("/items", "filtered")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		code   int
+		trace  string
+	}{
+		{"index", http.MethodGet, "/items", http.StatusOK, "before_action,index,after_action"},
+		{"edit", http.MethodGet, "/items/1/edit", http.StatusOK, "before_action,load,authorize,edit,after_action"},
+		{"update", http.MethodPut, "/items/1", http.StatusOK, "before_action,load,authorize,update,audit,after_action"},
+		{"short circuit", http.MethodGet, "/items/1/edit?deny=1", http.StatusForbidden, ""},
+		{"failed action skips after filters", http.MethodPut, "/items/1?fail=1", http.StatusUnprocessableEntity, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(loom, tt.method, tt.path)
+
+			if rec.Code != tt.code {
+				t.Errorf("status = %v, want %v", rec.Code, tt.code)
+			}
+
+			if got := rec.Header().Get("X-Trace"); got != tt.trace {
+				t.Errorf("trace = %v, want %v", got, tt.trace)
+			}
+		})
+	}
+}
@@ -2,9 +2,17 @@ package loom
 
 import (
 	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -14,19 +22,236 @@ type FlashKey struct{}
 
 const flashCookieName = "loom_flash_message"
 
-func FlashMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c echo.Context) error {
-		ctx := c.Request().Context()
+// minFlashKeyLen is the minimum length of the flash keys
+const minFlashKeyLen = 32
 
-		msg := get(c)
-		if msg != nil {
-			ctx = context.WithValue(ctx, FlashKey{}, msg)
-			clear(c)
+// flashStoreKey is the echo context key holding the flash cookie settings
+const flashStoreKey = "loom.flash"
+
+// FlashConfig configures the flash message cookie, it is part of AppConfig:
+//
+//	flash:
+//	  keys: ["${env:FLASH_KEY}", "${env:FLASH_KEY_PREVIOUS}"]
+//	  encrypt: true
+//	  secure: true
+type FlashConfig struct {
+	// Keys authenticate the cookie, new cookies use the first key while the others only
+	// decode existing cookies, so keys are rotated by adding the new key in front.
+	// Keys are random strings of at least 32 bytes: openssl rand -base64 32, they are only
+	// required by FlashMiddlewareWithConfig which panics at startup without them.
+	Keys []string `yaml:"keys" validate:"omitempty,dive,min=32" loom:"secret"`
+
+	// Encrypt encrypts the cookie with AES-GCM so its content can't be read,
+	// otherwise it is signed with HMAC-SHA256
+	Encrypt bool `yaml:"encrypt"`
+
+	// Secure sends the cookie over HTTPS only
+	Secure bool `yaml:"secure"`
+
+	// SameSite is lax, strict or none, lax by default
+	SameSite string `yaml:"same_site" validate:"omitempty,oneof=lax strict none"`
+}
+
+// FlashMiddlewareWithConfig moves the flash message of the previous request from its cookie
+// to the request context and lets the request set the next one (see FlashSuccess).
+// Cookies that were not issued with one of the keys are dropped.
+// It panics if no key is configured or a key is too short.
+// Usage: l.E.Use(loom.FlashMiddlewareWithConfig(cfg.Flash))
+func FlashMiddlewareWithConfig(config FlashConfig) echo.MiddlewareFunc {
+	store := newFlashStore(config)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(flashStoreKey, store)
+
+			ctx := c.Request().Context()
+
+			if cookie, err := c.Cookie(flashCookieName); err == nil {
+				if msg, err := store.decode(cookie.Value); err == nil {
+					ctx = context.WithValue(ctx, FlashKey{}, msg)
+				}
+
+				clear(c)
+			}
+
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
+
+// flashStore encodes flash messages into cookies
+type flashStore struct {
+	// keys are derived from the configured keys, for HMAC-SHA256 or AES-256
+	keys     [][]byte
+	aead     []cipher.AEAD
+	encrypt  bool
+	secure   bool
+	sameSite http.SameSite
+}
+
+func newFlashStore(config FlashConfig) *flashStore {
+	if len(config.Keys) == 0 {
+		panic("loom: flash middleware requires at least one key (see FlashConfig.Keys)")
+	}
+
+	for i, key := range config.Keys {
+		if len(key) < minFlashKeyLen {
+			panic(fmt.Sprintf("loom: flash key %d is shorter than %d bytes", i, minFlashKeyLen))
+		}
+	}
+
+	store := &flashStore{
+		encrypt:  config.Encrypt,
+		secure:   config.Secure,
+		sameSite: http.SameSiteLaxMode,
+	}
+
+	switch config.SameSite {
+	case "strict":
+		store.sameSite = http.SameSiteStrictMode
+	case "none":
+		store.sameSite = http.SameSiteNoneMode
+	}
+
+	purpose := "loom flash sign"
+	if config.Encrypt {
+		purpose = "loom flash encrypt"
+	}
+
+	for _, key := range config.Keys {
+		// derive a fixed size key dedicated to flash cookies from the configured secret
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(purpose))
+
+		derived := mac.Sum(nil)
+
+		store.keys = append(store.keys, derived)
+
+		if config.Encrypt {
+			block, err := aes.NewCipher(derived)
+			if err != nil {
+				panic(fmt.Sprintf("loom: invalid flash key: %v", err))
+			}
+
+			aead, err := cipher.NewGCM(block)
+			if err != nil {
+				panic(fmt.Sprintf("loom: invalid flash key: %v", err))
+			}
+
+			store.aead = append(store.aead, aead)
+		}
+	}
+
+	return store
+}
+
+// errInvalidFlash is returned for cookies that were tampered with or issued with an unknown key
+var errInvalidFlash = errors.New("invalid flash cookie")
+
+// encode returns the cookie value of the message, signed or encrypted with the first key
+func (s *flashStore) encode(msg *FlashMessage) (string, error) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	if s.encrypt {
+		nonce := make([]byte, s.aead[0].NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return "", err
+		}
+
+		// the cookie name is authenticated so the value can't be replayed as another cookie
+		sealed := s.aead[0].Seal(nonce, nonce, data, []byte(flashCookieName))
+
+		return base64.RawURLEncoding.EncodeToString(sealed), nil
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(data)
+
+	return payload + "." + base64.RawURLEncoding.EncodeToString(s.sign(s.keys[0], payload)), nil
+}
+
+// decode returns the message of a cookie value accepted by any of the keys
+func (s *flashStore) decode(value string) (*FlashMessage, error) {
+	data, err := s.open(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var msg FlashMessage
+
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, errInvalidFlash
+	}
+
+	return &msg, nil
+}
+
+func (s *flashStore) open(value string) ([]byte, error) {
+	if s.encrypt {
+		sealed, err := base64.RawURLEncoding.DecodeString(value)
+		if err != nil {
+			return nil, errInvalidFlash
+		}
+
+		for _, aead := range s.aead {
+			if len(sealed) < aead.NonceSize() {
+				return nil, errInvalidFlash
+			}
+
+			nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+			if data, err := aead.Open(nil, nonce, ciphertext, []byte(flashCookieName)); err == nil {
+				return data, nil
+			}
 		}
 
-		c.SetRequest(c.Request().WithContext(ctx))
+		return nil, errInvalidFlash
+	}
+
+	payload, signature, ok := strings.Cut(value, ".")
+	if !ok {
+		return nil, errInvalidFlash
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return nil, errInvalidFlash
+	}
+
+	for _, key := range s.keys {
+		if hmac.Equal(mac, s.sign(key, payload)) {
+			data, err := base64.RawURLEncoding.DecodeString(payload)
+			if err != nil {
+				return nil, errInvalidFlash
+			}
+
+			return data, nil
+		}
+	}
+
+	return nil, errInvalidFlash
+}
+
+// sign returns the HMAC of the payload, bound to the cookie name
+func (s *flashStore) sign(key []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(flashCookieName + "|" + payload))
+
+	return mac.Sum(nil)
+}
 
-		return next(c)
+func (s *flashStore) cookie(value string) *http.Cookie {
+	return &http.Cookie{
+		Name:     flashCookieName,
+		Value:    value,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   s.secure,
+		SameSite: s.sameSite,
 	}
 }
 
@@ -50,12 +275,6 @@ type FlashMessage struct {
 	Params  map[string]string
 }
 
-func (m FlashMessage) Encode() string {
-	data, _ := json.Marshal(m)
-
-	return encode(data)
-}
-
 func FlashSuccessNow(c echo.Context, msg string, kv ...string) {
 	setNow(c, newMessage(msg, "success", kv...))
 }
@@ -85,57 +304,33 @@ func FlashError(c echo.Context, msg string, kv ...string) {
 	set(c, newMessage(msg, "error", kv...))
 }
 
+// set sets the flash message cookie for the next request, it requires FlashMiddlewareWithConfig
 func set(c echo.Context, msg *FlashMessage) {
-	c.SetCookie(&http.Cookie{
-		Name:     flashCookieName,
-		Value:    msg.Encode(),
-		Path:     "/",
-		HttpOnly: true,
-	})
-}
-
-func get(c echo.Context) *FlashMessage {
-	cookie, err := c.Cookie(flashCookieName)
-	if err != nil {
-		return nil
-	}
-
-	if cookie == nil {
-		return nil
-	}
-
-	data, err := decode(cookie.Value)
-	if err != nil {
-		return nil
+	store, ok := c.Get(flashStoreKey).(*flashStore)
+	if !ok {
+		c.Logger().Error("loom: flash message dropped, FlashMiddlewareWithConfig is not used")
+		return
 	}
 
-	var m FlashMessage
-
-	err = json.Unmarshal(data, &m)
+	value, err := store.encode(msg)
 	if err != nil {
-		return nil
+		c.Logger().Error(fmt.Errorf("loom: failed to encode flash message: %w", err))
+		return
 	}
 
-	return &m
+	c.SetCookie(store.cookie(value))
 }
 
+// clear expires the flash message cookie
 func clear(c echo.Context) {
-	c.SetCookie(
-		&http.Cookie{
-			Name:     flashCookieName,
-			Value:    "",
-			Path:     "/",
-			MaxAge:   -1,
-			Expires:  time.Unix(0, 0),
-			HttpOnly: true,
-		},
-	)
-}
+	store, ok := c.Get(flashStoreKey).(*flashStore)
+	if !ok {
+		return
+	}
 
-func encode(src []byte) string {
-	return base64.URLEncoding.EncodeToString(src)
-}
+	cookie := store.cookie("")
+	cookie.MaxAge = -1
+	cookie.Expires = time.Unix(0, 0)
 
-func decode(src string) ([]byte, error) {
-	return base64.URLEncoding.DecodeString(src)
+	c.SetCookie(cookie)
 }
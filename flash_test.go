@@ -1,9 +1,11 @@
 package loom
 
 import (
-	"encoding/json"
+	"encoding/base64"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/labstack/echo/v4"
@@ -11,38 +13,113 @@ import (
 
 // Generated by Claude
 
-func TestFlashMessage_Encode(t *testing.T) {
-	msg := &FlashMessage{
-		Message: "Test message",
-		Type:    "success",
-		Params:  map[string]string{"key": "value"},
+const testFlashKey = "0123456789abcdef0123456789abcdef"
+
+func TestFlashStore_EncodeDecode(t *testing.T) {
+	for _, encrypt := range []bool{false, true} {
+		store := newFlashStore(FlashConfig{Keys: []string{testFlashKey}, Encrypt: encrypt})
+
+		msg := &FlashMessage{
+			Message: "Test message",
+			Type:    "success",
+			Params:  map[string]string{"key": "value"},
+		}
+
+		encoded, err := store.encode(msg)
+		if err != nil {
+			t.Fatalf("encode() error = %v", err)
+		}
+
+		if encrypt && strings.Contains(encoded, ".") {
+			t.Errorf("encrypted value %q looks signed", encoded)
+		}
+
+		decoded, err := store.decode(encoded)
+		if err != nil {
+			t.Fatalf("decode() encrypt=%v error = %v", encrypt, err)
+		}
+
+		if !reflect.DeepEqual(decoded, msg) {
+			t.Errorf("decode() encrypt=%v = %+v, want %+v", encrypt, decoded, msg)
+		}
 	}
+}
+
+func TestFlashStore_DecodeForged(t *testing.T) {
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"Message":"forged","Type":"success"}`))
+
+	for _, encrypt := range []bool{false, true} {
+		store := newFlashStore(FlashConfig{Keys: []string{testFlashKey}, Encrypt: encrypt})
+		other := newFlashStore(FlashConfig{Keys: []string{strings.Repeat("x", 32)}, Encrypt: encrypt})
 
-	encoded := msg.Encode()
-	if encoded == "" {
-		t.Error("FlashMessage.Encode() returned empty string")
+		valid, _ := store.encode(newMessage("valid", "success"))
+		foreign, _ := other.encode(newMessage("foreign", "success"))
+
+		tampered := []byte(valid)
+		tampered[len(tampered)/2] ^= 1
+
+		for _, value := range []string{"", forged, forged + ".", forged + "." + forged, foreign, string(tampered), "%%%"} {
+			if msg, err := store.decode(value); err == nil {
+				t.Errorf("decode(%q) encrypt=%v = %+v, want error", value, encrypt, msg)
+			}
+		}
 	}
+}
+
+func TestFlashStore_KeyRotation(t *testing.T) {
+	newKey := strings.Repeat("n", 32)
+
+	for _, encrypt := range []bool{false, true} {
+		old := newFlashStore(FlashConfig{Keys: []string{testFlashKey}, Encrypt: encrypt})
+		rotated := newFlashStore(FlashConfig{Keys: []string{newKey, testFlashKey}, Encrypt: encrypt})
+		current := newFlashStore(FlashConfig{Keys: []string{newKey}, Encrypt: encrypt})
 
-	// Decode and verify
-	decoded, err := decode(encoded)
-	if err != nil {
-		t.Errorf("decode() error = %v", err)
+		value, _ := old.encode(newMessage("before rotation", "success"))
+
+		if _, err := rotated.decode(value); err != nil {
+			t.Errorf("decode() of a cookie issued with the previous key encrypt=%v error = %v", encrypt, err)
+		}
+
+		// new cookies are issued with the first key
+		value, _ = rotated.encode(newMessage("after rotation", "success"))
+
+		if _, err := current.decode(value); err != nil {
+			t.Errorf("decode() of a cookie issued after rotation encrypt=%v error = %v", encrypt, err)
+		}
 	}
+}
 
-	var decodedMsg FlashMessage
-	err = json.Unmarshal(decoded, &decodedMsg)
-	if err != nil {
-		t.Errorf("json.Unmarshal() error = %v", err)
+func TestNewFlashStore_InvalidKeys(t *testing.T) {
+	for _, keys := range [][]string{nil, {testFlashKey, "short"}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("FlashMiddlewareWithConfig() with keys %q did not panic", keys)
+				}
+			}()
+
+			FlashMiddlewareWithConfig(FlashConfig{Keys: keys})
+		}()
 	}
+}
 
-	if decodedMsg.Message != msg.Message {
-		t.Errorf("decoded message = %v, want %v", decodedMsg.Message, msg.Message)
+func TestFlashConfig_Validation(t *testing.T) {
+	config := AppConfig{
+		Host:  ":8080",
+		DB:    DBConfig{Name: "app"},
+		Flash: FlashConfig{Keys: []string{testFlashKey, "short"}},
 	}
-	if decodedMsg.Type != msg.Type {
-		t.Errorf("decoded type = %v, want %v", decodedMsg.Type, msg.Type)
+
+	err := ValidateConfig(&config)
+	if err == nil || err.Error() != "invalid config:\n  flash.keys[1]: must be at least 32" {
+		t.Errorf("ValidateConfig() error = %v, want the short key reported", err)
 	}
-	if decodedMsg.Params["key"] != msg.Params["key"] {
-		t.Errorf("decoded params = %v, want %v", decodedMsg.Params, msg.Params)
+
+	// apps without flash messages don't configure keys, FlashMiddlewareWithConfig requires them
+	config.Flash.Keys = nil
+
+	if err := ValidateConfig(&config); err != nil {
+		t.Errorf("ValidateConfig() error = %v, want no flash keys allowed", err)
 	}
 }
 
@@ -103,150 +180,150 @@ func TestNewMessage(t *testing.T) {
 	}
 }
 
-func TestEncodeDecode(t *testing.T) {
-	testData := []byte("test data for encoding")
+// flashRequest runs handler behind the flash middleware with the cookies of req
+func flashRequest(t *testing.T, config FlashConfig, req *http.Request, handler echo.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
 
-	encoded := encode(testData)
-	if encoded == "" {
-		t.Error("encode() returned empty string")
-	}
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
 
-	decoded, err := decode(encoded)
-	if err != nil {
-		t.Errorf("decode() error = %v", err)
+	if err := FlashMiddlewareWithConfig(config)(handler)(c); err != nil {
+		t.Fatalf("FlashMiddlewareWithConfig() error = %v", err)
 	}
 
-	if string(decoded) != string(testData) {
-		t.Errorf("decode() = %v, want %v", string(decoded), string(testData))
+	return rec
+}
+
+func flashCookie(rec *httptest.ResponseRecorder) *http.Cookie {
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == flashCookieName {
+			return cookie
+		}
 	}
+
+	return nil
 }
 
 func TestFlashMiddleware(t *testing.T) {
-	e := echo.New()
-	req := httptest.NewRequest(http.MethodGet, "/", nil)
-	rec := httptest.NewRecorder()
-	c := e.NewContext(req, rec)
+	config := FlashConfig{Keys: []string{testFlashKey}}
 
-	// Create a flash message cookie
-	flashMsg := &FlashMessage{
-		Message: "test message",
-		Type:    "success",
-		Params:  map[string]string{},
-	}
-	cookie := &http.Cookie{
-		Name:  flashCookieName,
-		Value: flashMsg.Encode(),
+	rec := flashRequest(t, config, httptest.NewRequest(http.MethodPost, "/", nil), func(c echo.Context) error {
+		FlashSuccess(c, "test message", "key", "value")
+		return nil
+	})
+
+	cookie := flashCookie(rec)
+	if cookie == nil {
+		t.Fatal("Flash cookie was not set")
 	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
 	req.AddCookie(cookie)
 
 	called := false
-	handler := func(c echo.Context) error {
+
+	rec = flashRequest(t, config, req, func(c echo.Context) error {
 		called = true
-		// Check if flash message is in context
-		flashFromCtx := c.Request().Context().Value(FlashKey{})
-		if flashFromCtx == nil {
-			t.Error("Flash message not found in context")
+
+		msg, ok := c.Request().Context().Value(FlashKey{}).(*FlashMessage)
+		if !ok {
+			t.Fatal("Flash message not found in context")
 		}
-		return nil
-	}
 
-	middleware := FlashMiddleware(handler)
-	err := middleware(c)
+		if msg.Message != "test message" || msg.Type != "success" || msg.Params["key"] != "value" {
+			t.Errorf("Flash message = %+v", msg)
+		}
+
+		return nil
+	})
 
-	if err != nil {
-		t.Errorf("FlashMiddleware() error = %v", err)
-	}
 	if !called {
 		t.Error("Handler was not called")
 	}
+
+	// the message is only shown once
+	if cookie := flashCookie(rec); cookie == nil || cookie.MaxAge >= 0 {
+		t.Errorf("Flash cookie was not cleared: %+v", cookie)
+	}
 }
 
-func TestFlashSet(t *testing.T) {
-	e := echo.New()
+func TestFlashMiddleware_ForgedCookie(t *testing.T) {
+	forged := base64.URLEncoding.EncodeToString([]byte(`{"Message":"<script>","Type":"success"}`))
+
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
-	rec := httptest.NewRecorder()
-	c := e.NewContext(req, rec)
+	req.AddCookie(&http.Cookie{Name: flashCookieName, Value: forged})
 
-	FlashSuccess(c, "success message", "key", "value")
+	rec := flashRequest(t, FlashConfig{Keys: []string{testFlashKey}}, req, func(c echo.Context) error {
+		if msg := c.Request().Context().Value(FlashKey{}); msg != nil {
+			t.Errorf("Forged flash message in context: %+v", msg)
+		}
 
-	// Check if cookie was set
-	cookies := rec.Result().Cookies()
-	if len(cookies) == 0 {
-		t.Error("No cookies were set")
-		return
-	}
+		return nil
+	})
 
-	var flashCookie *http.Cookie
-	for _, cookie := range cookies {
-		if cookie.Name == flashCookieName {
-			flashCookie = cookie
-			break
-		}
+	if cookie := flashCookie(rec); cookie == nil || cookie.MaxAge >= 0 {
+		t.Errorf("Forged flash cookie was not cleared: %+v", cookie)
 	}
+}
 
-	if flashCookie == nil {
-		t.Error("Flash cookie was not set")
-		return
+func TestFlashSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   FlashConfig
+		secure   bool
+		sameSite http.SameSite
+	}{
+		{
+			name:     "defaults",
+			config:   FlashConfig{Keys: []string{testFlashKey}},
+			sameSite: http.SameSiteLaxMode,
+		},
+		{
+			name:     "secure strict",
+			config:   FlashConfig{Keys: []string{testFlashKey}, Encrypt: true, Secure: true, SameSite: "strict"},
+			secure:   true,
+			sameSite: http.SameSiteStrictMode,
+		},
 	}
 
-	// Decode and verify the flash message
-	decoded, err := decode(flashCookie.Value)
-	if err != nil {
-		t.Errorf("decode() error = %v", err)
-	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := flashRequest(t, tt.config, httptest.NewRequest(http.MethodPost, "/", nil), func(c echo.Context) error {
+				FlashSuccess(c, "success message", "key", "value")
+				return nil
+			})
+
+			cookie := flashCookie(rec)
+			if cookie == nil {
+				t.Fatal("Flash cookie was not set")
+			}
 
-	var msg FlashMessage
-	err = json.Unmarshal(decoded, &msg)
-	if err != nil {
-		t.Errorf("json.Unmarshal() error = %v", err)
-	}
+			if !cookie.HttpOnly || cookie.Secure != tt.secure || cookie.SameSite != tt.sameSite || cookie.Path != "/" {
+				t.Errorf("Flash cookie = %+v, want HttpOnly, Secure %v, SameSite %v", cookie, tt.secure, tt.sameSite)
+			}
 
-	if msg.Message != "success message" {
-		t.Errorf("Flash message = %v, want %v", msg.Message, "success message")
-	}
-	if msg.Type != "success" {
-		t.Errorf("Flash type = %v, want %v", msg.Type, "success")
-	}
-	if msg.Params["key"] != "value" {
-		t.Errorf("Flash params = %v, want %v", msg.Params["key"], "value")
+			msg, err := newFlashStore(tt.config).decode(cookie.Value)
+			if err != nil {
+				t.Fatalf("decode() error = %v", err)
+			}
+
+			if msg.Message != "success message" || msg.Type != "success" || msg.Params["key"] != "value" {
+				t.Errorf("Flash message = %+v", msg)
+			}
+		})
 	}
 }
 
-func TestFlashGet(t *testing.T) {
+func TestFlashSet_WithoutMiddleware(t *testing.T) {
 	e := echo.New()
-	req := httptest.NewRequest(http.MethodGet, "/", nil)
 	rec := httptest.NewRecorder()
-	c := e.NewContext(req, rec)
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
 
-	// Test with no cookie
-	msg := get(c)
-	if msg != nil {
-		t.Error("get() expected nil when no cookie, got message")
-	}
+	FlashSuccess(c, "success message")
 
-	// Test with valid cookie
-	flashMsg := &FlashMessage{
-		Message: "test message",
-		Type:    "warning",
-		Params:  map[string]string{"test": "param"},
-	}
-	cookie := &http.Cookie{
-		Name:  flashCookieName,
-		Value: flashMsg.Encode(),
-	}
-	req.AddCookie(cookie)
-
-	msg = get(c)
-	if msg == nil {
-		t.Error("get() expected message, got nil")
-		return
-	}
-
-	if msg.Message != flashMsg.Message {
-		t.Errorf("get().Message = %v, want %v", msg.Message, flashMsg.Message)
-	}
-	if msg.Type != flashMsg.Type {
-		t.Errorf("get().Type = %v, want %v", msg.Type, flashMsg.Type)
+	if cookie := flashCookie(rec); cookie != nil {
+		t.Errorf("Flash cookie set without the middleware: %+v", cookie)
 	}
 }
 
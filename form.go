@@ -1,5 +1,7 @@
 package loom
 
+import "encoding/json"
+
 type ViewModel struct {
 	Values map[string]string // map[string]any ?
 	Errors map[string]string
@@ -8,3 +10,28 @@ type ViewModel struct {
 func (vm *ViewModel) HasErrors() bool {
 	return len(vm.Errors) > 0
 }
+
+// validationErrorBody is the JSON body of a ViewModel, eg. for API clients of a form action:
+// {"message": "Validation failed", "errors": {"name": "name is required"}}
+type validationErrorBody struct {
+	Message string            `json:"message,omitempty"`
+	Errors  map[string]string `json:"errors"`
+}
+
+// MarshalJSON encodes the view model as a validation error body,
+// so it can be passed to loom.JSON as is
+func (vm ViewModel) MarshalJSON() ([]byte, error) {
+	body := validationErrorBody{
+		Errors: vm.Errors,
+	}
+
+	if body.Errors == nil {
+		body.Errors = make(map[string]string)
+	}
+
+	if vm.HasErrors() {
+		body.Message = "Validation failed"
+	}
+
+	return json.Marshal(body)
+}
@@ -0,0 +1,237 @@
+package loom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Dependency graph formats supported by WriteDepGraph
+const (
+	GraphText = "text"
+	GraphJSON = "json"
+	GraphDOT  = "dot"
+)
+
+// DepsGraphPath is the dev-only endpoint rendering the dependency graph, it is only served when
+// Loom.Debug is set. The format is picked with the format query param: /_loom/deps?format=dot
+const DepsGraphPath = "/_loom/deps"
+
+// DepInfo describes a registered dependency
+type DepInfo struct {
+	// Type and Label identify the dependency: "*sql.DB" and "replica"
+	Type  string `json:"type"`
+	Label string `json:"label,omitempty"`
+
+	// Lifetime is singleton, transient or scoped, instances registered with Add are singletons
+	Lifetime string `json:"lifetime"`
+
+	// Factory reports whether the dependency is built by a factory (see Provide)
+	Factory bool `json:"factory"`
+
+	// Source is where the dependency was registered: "cmd/app/main.go:24"
+	Source string `json:"source"`
+
+	// Consumers are the controllers it is injected into and the dependencies whose factories resolve it
+	Consumers []string `json:"consumers"`
+}
+
+// ID returns the identifier of the dependency used by consumers: "*sql.DB[replica]"
+func (i DepInfo) ID() string {
+	if i.Label == "" {
+		return i.Type
+	}
+
+	return fmt.Sprintf("%s[%s]", i.Type, i.Label)
+}
+
+// registration describes how a dependency was registered and who consumes it
+type registration struct {
+	lifetime  Lifetime
+	factory   bool
+	source    string
+	consumers []string
+}
+
+// consume records that consumer (a controller or a dependency) depends on key,
+// in the registry owning the registration
+func (r *registry) consume(key depKey, consumer string) {
+	for ; r != nil; r = r.parent {
+		r.mu.Lock()
+		reg, ok := r.registrations[key]
+
+		if ok && !slices.Contains(reg.consumers, consumer) {
+			reg.consumers = append(reg.consumers, consumer)
+		}
+
+		r.mu.Unlock()
+
+		if ok {
+			return
+		}
+	}
+}
+
+// Graph returns the registered dependencies in registration order along with their consumers.
+// Consumers are recorded as dependencies get resolved, so the graph is complete once
+// the controllers are registered and the factories were built (see Deps.Validate).
+func (d *Deps) Graph() []DepInfo {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	graph := make([]DepInfo, 0, len(d.order))
+
+	for _, key := range d.order {
+		reg := d.registrations[key]
+		if reg == nil {
+			continue
+		}
+
+		graph = append(graph, DepInfo{
+			Type:      key.t.String(),
+			Label:     key.label,
+			Lifetime:  reg.lifetime.String(),
+			Factory:   reg.factory,
+			Source:    reg.source,
+			Consumers: slices.Clone(reg.consumers),
+		})
+
+		if graph[len(graph)-1].Consumers == nil {
+			graph[len(graph)-1].Consumers = []string{}
+		}
+	}
+
+	return graph
+}
+
+// WriteDepGraph renders the dependency graph as a text table, JSON or Graphviz DOT
+// Usage: loom.WriteDepGraph(os.Stdout, deps.Graph(), loom.GraphDOT)
+func WriteDepGraph(w io.Writer, graph []DepInfo, format string) error {
+	switch format {
+	case GraphText, "":
+		return writeDepGraphText(w, graph)
+	case GraphJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(graph)
+	case GraphDOT:
+		return writeDepGraphDOT(w, graph)
+	}
+
+	return fmt.Errorf("unknown graph format %q, expected text, json or dot", format)
+}
+
+func writeDepGraphText(w io.Writer, graph []DepInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "DEPENDENCY\tLIFETIME\tSOURCE\tCONSUMERS")
+
+	for _, dep := range graph {
+		lifetime := dep.Lifetime
+		if dep.Factory {
+			lifetime += " (factory)"
+		}
+
+		consumers := strings.Join(dep.Consumers, ", ")
+		if consumers == "" {
+			consumers = "-"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", dep.ID(), lifetime, dep.Source, consumers)
+	}
+
+	return tw.Flush()
+}
+
+// writeDepGraphDOT renders an edge from every dependency to its consumers,
+// controllers are drawn as boxes
+func writeDepGraphDOT(w io.Writer, graph []DepInfo) error {
+	var b strings.Builder
+
+	b.WriteString("digraph deps {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=ellipse];\n")
+
+	deps := make(map[string]bool, len(graph))
+
+	for _, dep := range graph {
+		deps[dep.ID()] = true
+
+		fmt.Fprintf(&b, "  %q [label=%q];\n", dep.ID(), dep.ID()+"\n"+dep.Lifetime)
+	}
+
+	var controllers []string
+
+	for _, dep := range graph {
+		for _, consumer := range dep.Consumers {
+			if !deps[consumer] && !slices.Contains(controllers, consumer) {
+				controllers = append(controllers, consumer)
+			}
+		}
+	}
+
+	for _, ctrl := range controllers {
+		fmt.Fprintf(&b, "  %q [shape=box];\n", ctrl)
+	}
+
+	for _, dep := range graph {
+		for _, consumer := range dep.Consumers {
+			fmt.Fprintf(&b, "  %q -> %q;\n", dep.ID(), consumer)
+		}
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// depsGraphHandler serves the dependency graph at DepsGraphPath in debug mode
+func (l *Loom) depsGraphHandler(c echo.Context) error {
+	if !l.Debug {
+		return echo.ErrNotFound
+	}
+
+	format := c.QueryParam("format")
+
+	contentType := echo.MIMETextPlainCharsetUTF8
+	if format == GraphJSON {
+		contentType = echo.MIMEApplicationJSONCharsetUTF8
+	}
+
+	var b strings.Builder
+
+	if err := WriteDepGraph(&b, l.Deps.Graph(), format); err != nil {
+		return NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return c.Blob(http.StatusOK, contentType, []byte(b.String()))
+}
+
+// callerSource returns the file:line of the caller, skip is the number of frames above
+// the function calling callerSource. Paths within the working directory are made relative.
+func callerSource(skip int) string {
+	_, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return "unknown"
+	}
+
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+	}
+
+	return fmt.Sprintf("%s:%d", file, line)
+}
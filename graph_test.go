@@ -0,0 +1,146 @@
+package loom
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type mailer struct{}
+
+type graphController struct {
+	Controller
+
+	Repo   *repo   `loom:"inject"`
+	Mailer *mailer `loom:"inject,label=smtp"`
+}
+
+func newGraphApp() *Loom {
+	deps := NewDeps()
+
+	Add(deps, "dsn")
+	AddWithLabel(deps, &mailer{}, "smtp")
+
+	Provide(deps, func(d *Deps) (*repo, error) {
+		dsn, err := Get[string](d)
+		return &repo{id: len(dsn)}, err
+	})
+
+	ProvideScoped(deps, func(d *Deps) (*unitOfWork, error) {
+		_, err := Get[*repo](d)
+		return &unitOfWork{}, err
+	})
+
+	l := New(deps)
+	l.ValidateDeps = true
+
+	Register[*graphController](l)
+
+	return l
+}
+
+func TestDeps_Graph(t *testing.T) {
+	l := newGraphApp()
+
+	if err := l.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	graph := l.Deps.Graph()
+
+	want := []struct {
+		id        string
+		lifetime  string
+		factory   bool
+		consumers []string
+	}{
+		{"string", "singleton", false, []string{"*loom.repo"}},
+		{"*loom.mailer[smtp]", "singleton", false, []string{"*loom.graphController"}},
+		{"*loom.repo", "singleton", true, []string{"*loom.graphController", "*loom.unitOfWork"}},
+		{"*loom.unitOfWork", "scoped", true, []string{}},
+	}
+
+	if len(graph) != len(want) {
+		t.Fatalf("Graph() = %+v, want %d dependencies", graph, len(want))
+	}
+
+	for i, w := range want {
+		dep := graph[i]
+
+		if dep.ID() != w.id || dep.Lifetime != w.lifetime || dep.Factory != w.factory {
+			t.Errorf("Graph()[%d] = %+v, want %+v", i, dep, w)
+		}
+
+		if strings.Join(dep.Consumers, ",") != strings.Join(w.consumers, ",") {
+			t.Errorf("Graph()[%d].Consumers = %v, want %v", i, dep.Consumers, w.consumers)
+		}
+
+		if !strings.HasPrefix(dep.Source, "graph_test.go:") {
+			t.Errorf("Graph()[%d].Source = %v, want registration site in graph_test.go", i, dep.Source)
+		}
+	}
+}
+
+func TestWriteDepGraph(t *testing.T) {
+	l := newGraphApp()
+
+	if err := l.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	graph := l.Deps.Graph()
+
+	var dot bytes.Buffer
+	if err := WriteDepGraph(&dot, graph, GraphDOT); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`"*loom.repo" -> "*loom.graphController";`,
+		`"*loom.mailer[smtp]" -> "*loom.graphController";`,
+		`"*loom.graphController" [shape=box];`,
+	} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("DOT graph does not contain %s:\n%s", want, dot.String())
+		}
+	}
+
+	var text bytes.Buffer
+	if err := WriteDepGraph(&text, graph, GraphText); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(text.String(), "singleton (factory)") {
+		t.Errorf("text graph = %s", text.String())
+	}
+
+	if err := WriteDepGraph(&text, graph, "svg"); err == nil {
+		t.Error("WriteDepGraph() expected error for unknown format")
+	}
+}
+
+func TestDepsGraphEndpoint(t *testing.T) {
+	l := newGraphApp()
+
+	if rec := serve(l, http.MethodGet, DepsGraphPath); rec.Code != http.StatusNotFound {
+		t.Errorf("status = %v, want graph hidden outside of debug mode", rec.Code)
+	}
+
+	l.Debug = true
+
+	rec := serve(l, http.MethodGet, DepsGraphPath+"?format=json")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %v, want %v", rec.Code, http.StatusOK)
+	}
+
+	var graph []DepInfo
+	if err := json.Unmarshal(rec.Body.Bytes(), &graph); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(graph) != 4 {
+		t.Errorf("graph has %d dependencies, want 4", len(graph))
+	}
+}
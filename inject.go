@@ -0,0 +1,92 @@
+package loom
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// injectTag is the struct tag controlling dependency injection into controller fields:
+//
+//	DB      *sql.DB      `loom:"inject"`               // required
+//	Replica *sql.DB      `loom:"inject,label=replica"` // required, registered with AddWithLabel
+//	Mailer  Mailer                                     // exported fields are injected when registered
+//	Clock   Clock        `loom:"-"`                    // never injected
+//
+// Only exported fields can be injected, tagging an unexported field is a configuration error.
+const injectTag = "loom"
+
+// injectField describes how a controller field is injected
+type injectField struct {
+	label    string
+	required bool
+}
+
+// parseInjectTag returns how the field is injected and whether it should be injected at all
+func parseInjectTag(field reflect.StructField) (injectField, bool) {
+	tag, tagged := field.Tag.Lookup(injectTag)
+
+	if !tagged {
+		// embedded types such as loom.Controller are never injected implicitly
+		return injectField{}, field.IsExported() && !field.Anonymous
+	}
+
+	parts := strings.Split(tag, ",")
+	if parts[0] != "inject" {
+		return injectField{}, false
+	}
+
+	f := injectField{required: true}
+
+	for _, opt := range parts[1:] {
+		if label, ok := strings.CutPrefix(opt, "label="); ok {
+			f.label = label
+		}
+	}
+
+	return f, true
+}
+
+// inject sets the fields of the controller struct value v from Deps and returns an error
+// for every required field that could not be resolved
+func (l *Loom) inject(v reflect.Value) []error {
+	var errs []error
+
+	structType := v.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+
+		opts, ok := parseInjectTag(field)
+		if !ok {
+			continue
+		}
+
+		if !field.IsExported() {
+			errs = append(errs, fmt.Errorf("%s.%s: unexported fields can't be injected, export the field", structType.Name(), field.Name))
+			continue
+		}
+
+		if !opts.required && !l.Deps.has(field.Type, opts.label) {
+			continue
+		}
+
+		l.Deps.consume(depKey{t: field.Type, label: opts.label}, reflect.PointerTo(structType).String())
+
+		service, err := l.Deps.get(field.Type, opts.label)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s.%s: %w", structType.Name(), field.Name, err))
+			continue
+		}
+
+		value := reflect.ValueOf(service)
+		if !value.IsValid() || !value.Type().AssignableTo(field.Type) {
+			errs = append(errs, fmt.Errorf("%s.%s: service of type %T is not assignable to %v", structType.Name(), field.Name, service, field.Type))
+			continue
+		}
+
+		v.Field(i).Set(value)
+	}
+
+	return errs
+}
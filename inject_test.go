@@ -0,0 +1,119 @@
+package loom
+
+import (
+	"strings"
+	"testing"
+)
+
+type injectedController struct {
+	Controller
+
+	Service   *testService  `loom:"inject"`
+	Secondary *testService  `loom:"inject,label=secondary"`
+	Named     testInterface // exported, injected when registered
+	Optional  *testImplementation
+	Skipped   *testService      `loom:"-"`
+	Cached    string            `loom:"cache"` // other loom tags are ignored
+	Counts    map[string]string // exported but never registered
+
+	initCalled bool
+}
+
+func (ic *injectedController) Init() error {
+	ic.initCalled = true
+	return nil
+}
+
+type otherController struct {
+	Controller
+
+	Missing *testImplementation `loom:"inject,label=missing"`
+}
+
+type unexportedController struct {
+	Controller
+
+	service *testService `loom:"inject"`
+}
+
+func TestRegister_Inject(t *testing.T) {
+	deps := NewDeps()
+
+	Add(deps, &testService{Name: "primary"})
+	AddWithLabel(deps, &testService{Name: "secondary"}, "secondary")
+	Add[testInterface](deps, &testImplementation{name: "named"})
+
+	loom := New(deps)
+
+	Register[*injectedController](loom)
+
+	if err := loom.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	ctrl := loom.controllerRegistry["InjectedController"].Instance.(*injectedController)
+
+	if ctrl.Service == nil || ctrl.Service.Name != "primary" {
+		t.Errorf("service = %v, want primary", ctrl.Service)
+	}
+	if ctrl.Secondary == nil || ctrl.Secondary.Name != "secondary" {
+		t.Errorf("secondary = %v, want secondary", ctrl.Secondary)
+	}
+	if ctrl.Named == nil || ctrl.Named.GetName() != "named" {
+		t.Errorf("Named = %v, want named", ctrl.Named)
+	}
+	if ctrl.Optional != nil {
+		t.Errorf("Optional = %v, want nil", ctrl.Optional)
+	}
+	if ctrl.Skipped != nil {
+		t.Errorf("Skipped = %v, want nil", ctrl.Skipped)
+	}
+	if ctrl.Deps != deps {
+		t.Error("Controller.Deps not set")
+	}
+	if !ctrl.initCalled {
+		t.Error("Init was not called")
+	}
+}
+
+func TestRegister_InjectMissing(t *testing.T) {
+	loom := New(NewDeps())
+
+	Register[*injectedController](loom)
+	Register[*otherController](loom)
+
+	err := loom.Validate()
+	if err == nil {
+		t.Fatal("Validate() expected error for missing dependencies, got nil")
+	}
+
+	for _, field := range []string{"injectedController.Service", "injectedController.Secondary", "otherController.Missing"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("Validate() error = %v, want it to list %s", err, field)
+		}
+	}
+
+	ctrl := loom.controllerRegistry["InjectedController"].Instance.(*injectedController)
+	if ctrl.initCalled {
+		t.Error("Init should not be called when dependencies are missing")
+	}
+
+	if err := loom.Start(":0"); err == nil {
+		t.Error("Start() expected validation error, got nil")
+	}
+}
+
+func TestRegister_InjectUnexported(t *testing.T) {
+	deps := NewDeps()
+
+	Add(deps, &testService{Name: "primary"})
+
+	loom := New(deps)
+
+	Register[*unexportedController](loom)
+
+	err := loom.Validate()
+	if err == nil || !strings.Contains(err.Error(), "unexportedController.service: unexported") {
+		t.Fatalf("Validate() error = %v, want the unexported field reported", err)
+	}
+}
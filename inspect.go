@@ -0,0 +1,153 @@
+package loom
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// InspectEnv is the environment variable that puts Loom in inspect mode.
+// It holds the path of the file the inspection report is written to.
+const InspectEnv = "LOOM_INSPECT"
+
+// Inspecting reports whether the application runs in inspect mode (see InspectEnv).
+// Loom configures everything as usual in inspect mode, but Start writes the
+// inspection report instead of serving, so apps can use it to skip side effects.
+func Inspecting() bool {
+	return os.Getenv(InspectEnv) != ""
+}
+
+// RouteInfo describes a route registered through Loom
+type RouteInfo struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+
+	// Name is the ctrlAction of the route: "contacts.show"
+	Name string `json:"name"`
+
+	// Controller and Action are the resolved controller type and method:
+	// "*controller.ContactsController" and "Show"
+	Controller string `json:"controller"`
+	Action     string `json:"action"`
+
+	// Middleware lists the middleware in the order it runs: the global middleware added with
+	// E.Pre and E.Use, then the group and route-level middleware
+	Middleware []string `json:"middleware"`
+}
+
+// InspectReport is what Loom writes in inspect mode, it is consumed by the loom CLI
+type InspectReport struct {
+	Routes  []RouteInfo  `json:"routes"`
+	Deps    []DepInfo    `json:"deps"`
+	Modules []ModuleInfo `json:"modules"`
+}
+
+// Routes returns the routes registered through Loom in registration order
+func (l *Loom) Routes() []RouteInfo {
+	global := l.globalMiddleware()
+
+	routes := make([]RouteInfo, len(l.routes))
+
+	for i, route := range l.routes {
+		route.Middleware = append(slices.Clip(global), route.Middleware...)
+		routes[i] = route
+	}
+
+	return routes
+}
+
+// globalMiddleware returns the names of the middleware added with E.Pre and E.Use, they run
+// before the group and route middleware of every route. Echo does not expose them so they are
+// read through reflection, the middleware Loom adds itself in New is left out.
+func (l *Loom) globalMiddleware() []string {
+	internal := []string{funcName(recoverMiddleware), funcName(l.contextMiddleware)}
+
+	e := reflect.ValueOf(l.E).Elem()
+
+	names := []string{}
+
+	for _, field := range []string{"premiddleware", "middleware"} {
+		m := e.FieldByName(field)
+		if m.Kind() != reflect.Slice {
+			continue
+		}
+
+		for i := 0; i < m.Len(); i++ {
+			if name := funcNameOf(m.Index(i)); !slices.Contains(internal, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	return names
+}
+
+// Inspect returns the inspection report of the configured application
+func (l *Loom) Inspect() InspectReport {
+	return InspectReport{
+		Routes:  l.Routes(),
+		Deps:    l.Deps.Graph(),
+		Modules: l.Modules(),
+	}
+}
+
+func (l *Loom) writeInspectReport(path string) error {
+	data, err := json.MarshalIndent(l.Inspect(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode inspect report: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write inspect report: %w", err)
+	}
+
+	return nil
+}
+
+// funcNames returns short names of middleware functions for the route table:
+// "github.com/labstack/echo/v4/middleware.CSRFWithConfig.func1" -> "middleware.CSRFWithConfig"
+func funcNames(m []echo.MiddlewareFunc) []string {
+	names := make([]string, 0, len(m))
+
+	for _, fn := range m {
+		names = append(names, funcName(fn))
+	}
+
+	return names
+}
+
+func funcName(fn any) string {
+	return funcNameOf(reflect.ValueOf(fn))
+}
+
+func funcNameOf(fn reflect.Value) string {
+	f := runtime.FuncForPC(fn.Pointer())
+	if f == nil {
+		return "unknown"
+	}
+
+	name := f.Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	name = strings.TrimSuffix(name, "-fm")
+
+	// drop the closure suffixes: .func1, .func1.2, .gowrap1
+	parts := strings.Split(name, ".")
+	for len(parts) > 2 && isClosureSuffix(parts[len(parts)-1]) {
+		parts = parts[:len(parts)-1]
+	}
+
+	return strings.Join(parts, ".")
+}
+
+var closureSuffix = regexp.MustCompile(`^((func|gowrap)\d+|\d+)$`)
+
+func isClosureSuffix(s string) bool {
+	return closureSuffix.MatchString(s)
+}
@@ -0,0 +1,81 @@
+package loom
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+func authMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return next
+}
+
+func TestLoom_Routes(t *testing.T) {
+	loom := New(NewDeps())
+
+	Register[*testController](loom)
+
+	loom.GET("/", "test.index")
+
+	admin := loom.Group("/admin", authMiddleware)
+	admin.GET("/:id", "test.show", middleware.BodyLimit("1M"))
+
+	want := []RouteInfo{
+		{
+			Method:     "GET",
+			Path:       "/",
+			Name:       "test.index",
+			Controller: "*loom.testController",
+			Action:     "Index",
+			Middleware: []string{},
+		},
+		{
+			Method:     "GET",
+			Path:       "/admin/:id",
+			Name:       "test.show",
+			Controller: "*loom.testController",
+			Action:     "Show",
+			Middleware: []string{"loom.authMiddleware", "middleware.BodyLimitWithConfig"},
+		},
+	}
+
+	if got := loom.Routes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Routes() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoom_Routes_GlobalMiddleware(t *testing.T) {
+	loom := New(NewDeps())
+
+	Register[*testController](loom)
+
+	loom.E.Pre(MethodOverrideMiddleware)
+	loom.E.Use(middleware.BodyLimit("1M"))
+
+	loom.GET("/", "test.index", authMiddleware)
+
+	want := []string{"loom.MethodOverrideMiddleware", "middleware.BodyLimitWithConfig", "loom.authMiddleware"}
+
+	if got := loom.Routes()[0].Middleware; !reflect.DeepEqual(got, want) {
+		t.Errorf("Routes() middleware = %v, want %v", got, want)
+	}
+}
+
+func TestFuncName(t *testing.T) {
+	tests := []struct {
+		fn   any
+		want string
+	}{
+		{FlashMiddlewareWithConfig, "loom.FlashMiddlewareWithConfig"},
+		{middleware.CSRF(), "middleware.CSRFWithConfig"},
+		{(&testController{}).Index, "loom.(*testController).Index"},
+	}
+
+	for _, tt := range tests {
+		if got := funcName(tt.fn); got != tt.want {
+			t.Errorf("funcName() = %v, want %v", got, tt.want)
+		}
+	}
+}
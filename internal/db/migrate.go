@@ -11,14 +11,17 @@ import (
 
 	"github.com/aneshas/loom"
 	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
-func GenMigration(description string) error {
+// GenMigration creates empty up and down migration files in migrationsPath,
+// numbered after the last migration in it
+func GenMigration(migrationsPath string, description string) error {
 	fmt.Printf("Generating migration file with description: %s\n", description)
 
 	description = strings.ToLower(description)
@@ -30,8 +33,6 @@ func GenMigration(description string) error {
 	}, description)
 	description = strings.ReplaceAll(description, " ", "_")
 
-	migrationsPath := "internal/db/migrations"
-
 	files, err := os.ReadDir(migrationsPath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -82,42 +83,52 @@ func GenMigration(description string) error {
 	return nil
 }
 
-// RunMigrations executes all migrations from the specified directory
-func RunMigrations(cfg *loom.AppConfig, migrationsPath string) error {
-	var m *migrate.Migrate
-	var err error
+// RunMigrations executes all migrations from the specified directory against the database.
+// The applied versions are tracked in migrationsTable, or in the default schema_migrations table if it is empty.
+// It connects through DBConfig.Open like the application does.
+func RunMigrations(cfg loom.DBConfig, migrationsPath string, migrationsTable string) error {
+	// the database is described from the settings of the url when it is set
+	cfg, err := cfg.WithURL()
+	if err != nil {
+		return err
+	}
+
+	conn, err := cfg.Open()
+	if err != nil {
+		return err
+	}
+
+	var (
+		driver     database.Driver
+		driverName string
+	)
 
 	if cfg.IsSQLite() {
-		// Use SQLite
-		log.Printf("Using SQLite database: %s", cfg.SQLiteDSN())
-
-		// Create migrate instance with file source and SQLite database
-		m, err = migrate.New(
-			fmt.Sprintf("file://%s", migrationsPath),
-			cfg.SQLiteDSN(),
-		)
-		if err != nil {
-			return fmt.Errorf("failed to create migrate instance: %w", err)
-		}
+		log.Printf("Using SQLite database: %s.db", cfg.Name)
+
+		driverName = "sqlite3"
+		driver, err = sqlite3.WithInstance(conn, &sqlite3.Config{MigrationsTable: migrationsTable})
 	} else {
-		// Use PostgreSQL
-		log.Printf("Using PostgreSQL database: %s", cfg.PostgresDSN())
-
-		// Create migrate instance with file source and PostgreSQL database
-		m, err = migrate.New(
-			fmt.Sprintf("file://%s", migrationsPath),
-			cfg.PostgresDSN(),
-		)
-		if err != nil {
-			return fmt.Errorf("failed to create migrate instance: %w", err)
-		}
+		log.Printf("Using PostgreSQL database: %s on %s", cfg.Name, cfg.Host)
+
+		driverName = "pgx"
+		driver, err = pgx.WithInstance(conn, &pgx.Config{MigrationsTable: migrationsTable})
+	}
+
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to create migrate driver: %w", err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance(fmt.Sprintf("file://%s", migrationsPath), driverName, driver)
+	if err != nil {
+		driver.Close()
+		return fmt.Errorf("failed to create migrate instance: %w", err)
 	}
 
 	defer func() {
-		if m != nil {
-			if _, err := m.Close(); err != nil {
-				log.Printf("Error closing migration: %v", err)
-			}
+		if _, err := m.Close(); err != nil {
+			log.Printf("Error closing migration: %v", err)
 		}
 	}()
 
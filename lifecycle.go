@@ -0,0 +1,194 @@
+package loom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+)
+
+// DefaultShutdownTimeout is how long Run waits for in-flight requests when Loom.ShutdownTimeout is not set
+const DefaultShutdownTimeout = 10 * time.Second
+
+// Hook is a function run when the application starts or stops (see OnStart and OnStop)
+type Hook func(ctx context.Context) error
+
+// Starter is implemented by controllers and dependencies that need to do work
+// (eg. warm up a cache or start a worker) before the server starts accepting requests.
+// A controller Start method routed as an action (eg. games.start) is not a start hook.
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// Stopper is implemented by controllers and dependencies that need to do work
+// once the server has stopped accepting requests.
+// A controller Stop method routed as an action (eg. timers.stop) is not a stop hook.
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+// OnStart registers a hook run by Run before the server starts accepting requests
+func (l *Loom) OnStart(hook Hook) {
+	l.onStart = append(l.onStart, hook)
+}
+
+// OnStop registers a hook run by Run once the in-flight requests are drained
+func (l *Loom) OnStop(hook Hook) {
+	l.onStop = append(l.onStop, hook)
+}
+
+// Run starts the http server on addr and blocks until ctx is done or the process
+// receives SIGINT or SIGTERM. It then stops accepting requests and waits up to
+// ShutdownTimeout for the in-flight ones before running the stop hooks.
+//
+// Start hooks run in this order: Starter dependencies in registration order, Starter controllers,
+// then the OnStart hooks. Stopping runs the OnStop hooks, Stopper controllers and Stopper dependencies
+// in reverse order, and finally closes every io.Closer in Deps (eg. *sql.DB) in reverse registration order.
+//
+// When LOOM_INSPECT is set the server is not started, instead the inspection report
+// is written to the file it points to (see Inspect). The configuration is not validated
+// then, so the CLI doesn't run the dependency factories.
+func (l *Loom) Run(ctx context.Context, addr string) error {
+	if Inspecting() {
+		return l.writeInspectReport(os.Getenv(InspectEnv))
+	}
+
+	if err := l.Validate(); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := l.start(ctx); err != nil {
+		return errors.Join(err, l.stop())
+	}
+
+	serverErr := make(chan error, 1)
+
+	go func() {
+		serverErr <- l.E.Start(addr)
+	}()
+
+	var err error
+
+	select {
+	case <-ctx.Done():
+	case err = <-serverErr:
+	}
+
+	if errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+
+	return errors.Join(err, l.shutdown())
+}
+
+func (l *Loom) shutdownTimeout() time.Duration {
+	if l.ShutdownTimeout > 0 {
+		return l.ShutdownTimeout
+	}
+
+	return DefaultShutdownTimeout
+}
+
+// shutdown drains the in-flight requests and runs the stop hooks
+func (l *Loom) shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), l.shutdownTimeout())
+	defer cancel()
+
+	var err error
+
+	if shutdownErr := l.E.Shutdown(ctx); shutdownErr != nil {
+		err = fmt.Errorf("failed to shut down server: %w", shutdownErr)
+	}
+
+	return errors.Join(err, l.stop())
+}
+
+// start runs the start hooks, Run stops everything if one of them fails
+func (l *Loom) start(ctx context.Context) error {
+	for _, service := range l.lifecycleServices() {
+		starter, ok := service.(Starter)
+		if !ok || l.routesAction(service, "Start") {
+			continue
+		}
+
+		if err := starter.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start %T: %w", service, err)
+		}
+	}
+
+	for _, hook := range l.onStart {
+		if err := hook(ctx); err != nil {
+			return fmt.Errorf("start hook failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// stop runs the stop hooks and closes the dependencies, all of them run even if some fail
+func (l *Loom) stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), l.shutdownTimeout())
+	defer cancel()
+
+	var errs []error
+
+	for _, hook := range slices.Backward(l.onStop) {
+		if err := hook(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stop hook failed: %w", err))
+		}
+	}
+
+	services := l.lifecycleServices()
+
+	for _, service := range slices.Backward(services) {
+		if stopper, ok := service.(Stopper); ok && !l.routesAction(service, "Stop") {
+			if err := stopper.Stop(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("failed to stop %T: %w", service, err))
+			}
+		}
+	}
+
+	for _, service := range slices.Backward(l.Deps.ordered()) {
+		if closer, ok := service.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("failed to close %T: %w", service, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// lifecycleServices returns the dependencies followed by the controllers, in registration order
+func (l *Loom) lifecycleServices() []any {
+	services := l.Deps.ordered()
+
+	for _, key := range l.controllerOrder {
+		services = append(services, l.controllerRegistry[key].Instance)
+	}
+
+	return services
+}
+
+// routesAction reports whether the method of a controller is routed as an action,
+// Start and Stop are valid action names so they are only lifecycle hooks when not routed
+func (l *Loom) routesAction(service any, method string) bool {
+	typeName := fmt.Sprintf("%T", service)
+
+	for _, route := range l.routes {
+		if route.Controller == typeName && route.Action == method {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,270 @@
+package loom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type events struct {
+	mu   sync.Mutex
+	list []string
+}
+
+func (e *events) add(event string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.list = append(e.list, event)
+}
+
+func (e *events) String() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return fmt.Sprint(e.list)
+}
+
+type lifecycleService struct {
+	name   string
+	events *events
+}
+
+func (s *lifecycleService) Start(ctx context.Context) error {
+	s.events.add("start " + s.name)
+	return nil
+}
+
+func (s *lifecycleService) Stop(ctx context.Context) error {
+	s.events.add("stop " + s.name)
+	return nil
+}
+
+func (s *lifecycleService) Close() error {
+	s.events.add("close " + s.name)
+	return nil
+}
+
+type closer struct {
+	name   string
+	events *events
+}
+
+func (c *closer) Close() error {
+	c.events.add("close " + c.name)
+	return nil
+}
+
+type lifecycleController struct {
+	Controller
+
+	Events *events `loom:"inject"`
+}
+
+func (lc *lifecycleController) Start(ctx context.Context) error {
+	lc.Events.add("start controller")
+	return nil
+}
+
+func (lc *lifecycleController) Stop(ctx context.Context) error {
+	lc.Events.add("stop controller")
+	return nil
+}
+
+func (lc *lifecycleController) Index(c echo.Context) error {
+	time.Sleep(100 * time.Millisecond)
+
+	return c.String(http.StatusOK, "done")
+}
+
+// listenerAddr waits for the server started by Run to listen
+func listenerAddr(t *testing.T, l *Loom) string {
+	t.Helper()
+
+	for range 100 {
+		if addr := l.E.ListenerAddr(); addr != nil {
+			return addr.String()
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("server did not start")
+
+	return ""
+}
+
+func TestRun(t *testing.T) {
+	ev := &events{}
+
+	deps := NewDeps()
+
+	Add(deps, ev)
+	Add(deps, &closer{name: "db", events: ev})
+	AddWithLabel(deps, &lifecycleService{name: "cache", events: ev}, "cache")
+
+	l := New(deps)
+	l.E.HideBanner = true
+	l.E.HidePort = true
+
+	l.OnStart(func(ctx context.Context) error {
+		ev.add("on start")
+		return nil
+	})
+
+	l.OnStop(func(ctx context.Context) error {
+		ev.add("on stop")
+		return nil
+	})
+
+	Register[*lifecycleController](l)
+
+	l.GET("/slow", "lifecycle.index")
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	runErr := make(chan error, 1)
+
+	go func() {
+		runErr <- l.Run(ctx, "127.0.0.1:0")
+	}()
+
+	addr := listenerAddr(t, l)
+
+	type result struct {
+		code int
+		err  error
+	}
+
+	inFlight := make(chan result, 1)
+
+	go func() {
+		res, err := http.Get("http://" + addr + "/slow")
+		if err != nil {
+			inFlight <- result{err: err}
+			return
+		}
+
+		defer res.Body.Close()
+
+		inFlight <- result{code: res.StatusCode}
+	}()
+
+	// let the request reach the handler before shutting down
+	time.Sleep(30 * time.Millisecond)
+	cancel()
+
+	if err := <-runErr; err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if res := <-inFlight; res.err != nil || res.code != http.StatusOK {
+		t.Errorf("in-flight request was not drained: %+v", res)
+	}
+
+	want := "[start cache start controller on start on stop stop controller stop cache close cache close db]"
+	if got := ev.String(); got != want {
+		t.Errorf("lifecycle events = %v, want %v", got, want)
+	}
+}
+
+func TestRun_StartHookFails(t *testing.T) {
+	ev := &events{}
+
+	deps := NewDeps()
+
+	Add(deps, &closer{name: "db", events: ev})
+
+	l := New(deps)
+
+	l.OnStart(func(ctx context.Context) error {
+		return errors.New("boom")
+	})
+
+	err := l.Run(context.Background(), "127.0.0.1:0")
+	if err == nil {
+		t.Fatal("Run() expected error, got nil")
+	}
+
+	if l.E.ListenerAddr() != nil {
+		t.Error("Run() started the server although a start hook failed")
+	}
+
+	if got := ev.String(); got != "[close db]" {
+		t.Errorf("lifecycle events = %v, want dependencies closed", got)
+	}
+}
+
+func TestDeps_Ordered(t *testing.T) {
+	deps := NewDeps()
+
+	first := &closer{name: "first"}
+	second := &closer{name: "second"}
+
+	Add(deps, first)
+	AddWithLabel(deps, second, "second")
+	AddWithLabel(deps, first, "again")
+	Add(deps, first)
+
+	RemoveWithLabel[*closer](deps, "second")
+	AddWithLabel(deps, second, "second")
+
+	got := deps.ordered()
+
+	if len(got) != 2 || got[0] != first || got[1] != second {
+		t.Errorf("ordered() = %v, want [first second]", got)
+	}
+}
+
+type timersController struct {
+	Controller
+
+	Events *events `loom:"inject"`
+}
+
+func (tc *timersController) Start(ctx context.Context) error {
+	tc.Events.add("start timer")
+	return nil
+}
+
+func (tc *timersController) Stop(ctx context.Context) error {
+	tc.Events.add("stop timer")
+	return nil
+}
+
+func TestStartStop_RoutedActions(t *testing.T) {
+	ev := &events{}
+
+	deps := NewDeps()
+
+	Add(deps, ev)
+
+	l := New(deps)
+
+	Register[*timersController](l)
+
+	l.POST("/timers/start", "timers.start")
+	l.POST("/timers/stop", "timers.stop")
+
+	if err := l.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	if err := l.start(context.Background()); err != nil {
+		t.Fatalf("start() error = %v", err)
+	}
+
+	if err := l.stop(); err != nil {
+		t.Fatalf("stop() error = %v", err)
+	}
+
+	if got := ev.String(); got != "[]" {
+		t.Errorf("lifecycle events = %v, want the routed actions not to run", got)
+	}
+}
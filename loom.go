@@ -1,10 +1,15 @@
 package loom
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/a-h/templ"
 	"github.com/go-playground/form"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
@@ -13,43 +18,154 @@ import (
 )
 
 func New(deps *Deps) *Loom {
-	return &Loom{
+	l := &Loom{
 		E:                  echo.New(),
 		Deps:               deps,
+		Renderer:           renderComponent,
 		controllerRegistry: make(map[string]*controller),
 		methodRegistry:     make(map[string]*methodCall),
+		formDecoder:        form.NewDecoder(),
+		validator:          newValidator(),
 	}
+
+	l.Router = &Router{l: l, echo: l.E}
+
+	l.E.HTTPErrorHandler = l.handleError
+	l.E.Use(recoverMiddleware, l.contextMiddleware)
+
+	l.E.GET(DepsGraphPath, l.depsGraphHandler)
+
+	return l
 }
 
 type Loom struct {
 	E *echo.Echo
 	*Deps
+	*Router
+
+	// Renderer renders the templ components returned by controller actions
+	// with the signature func(context.Context, In) (templ.Component, error).
+	// It defaults to rendering the bare component, apps set it to their layout aware render function.
+	Renderer RenderFunc
+
+	// Debug renders the dev error page with the stack trace, request details
+	// and the failing source line instead of ErrorPages. Do not enable it in production.
+	Debug bool
+
+	// ErrorPages are the components rendered (through Renderer) for error responses by status code.
+	// Server errors without a page of their own use the 500 page, other errors without a page
+	// get echo's default error response. Use ErrorFrom to access the error from the component.
+	ErrorPages map[int]templ.Component
+
+	// ShutdownTimeout is how long Run waits for in-flight requests to finish, see DefaultShutdownTimeout
+	ShutdownTimeout time.Duration
+
+	// ValidateDeps makes Validate, and so Run, build every dependency factory (see Deps.Validate),
+	// reporting failing factories at startup at the cost of singletons no longer being lazy
+	ValidateDeps bool
 
 	controllerRegistry map[string]*controller
+	controllerOrder    []string
 	methodRegistry     map[string]*methodCall
+
+	formDecoder *form.Decoder
+	validator   *validator.Validate
+
+	// errs are the configuration errors reported by Validate
+	errs []error
+
+	routes []RouteInfo
+
+	onStart []Hook
+	onStop  []Hook
+
+	modules []ModuleInfo
 }
 
 type controller struct {
 	Type     reflect.Type
 	Instance any
+
+	// bindings are the reflection-free actions registered with Bind, keyed by method name
+	bindings map[string]func(ctrl any, c echo.Context) error
+
+	// scoped controllers get a copy of Instance for every request
+	scoped bool
+}
+
+// newInstance returns a shallow copy of the controller template instance
+func (ctrl *controller) newInstance() any {
+	instance := reflect.New(ctrl.Type.Elem())
+	instance.Elem().Set(reflect.ValueOf(ctrl.Instance).Elem())
+
+	return instance.Interface()
+}
+
+// controllerKey returns the registry key of a controller pointer type.
+// Controllers are looked up by their title cased ctrlAction name (see handlerFor)
+// so unexported controller types need to be registered under the same key.
+func controllerKey(controllerType reflect.Type) string {
+	typeName := controllerType.Elem().Name()
+
+	return strings.ToUpper(typeName[:1]) + typeName[1:]
 }
 
 type methodCall struct {
 	controllerInstance any
 	method             reflect.Method
+
+	// signature details resolved once at registration (see newMethodCall)
+	stdContext bool
+	params     reflect.Type
+	renders    bool
+}
+
+// Validate reports every problem found while configuring Loom at once,
+// such as controller fields whose dependencies are not registered in Deps,
+// and dependency factories that fail to build when ValidateDeps is set (see Deps.Validate)
+func (g *Loom) Validate() error {
+	errs := g.errs
+
+	if g.ValidateDeps {
+		if err := g.Deps.Validate(); err != nil {
+			errs = append(slices.Clip(errs), err)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("invalid loom configuration:\n%w", errors.Join(errs...))
 }
 
+// Start starts the http server on addr, it is Run with a background context
 func (g *Loom) Start(addr string) error {
-	return g.E.Start(addr)
+	return g.Run(context.Background(), addr)
 }
 
 // Register registers a controller
+// A single controller instance is shared by all requests, see RegisterScoped for per-request instances.
 // Usage: loom.Register[*UsersController](l)
 func Register[T any](l *Loom) {
+	register[T](l, false)
+}
+
+// RegisterScoped registers a controller which gets a fresh instance for every request,
+// so filters and actions can safely keep request state (eg. the current user) in its fields.
+// Instances are shallow copies of a template instance that is injected and initialized once,
+// so injected dependencies are still shared.
+// loom gen controllers registers controller types marked with a //loom:scoped comment this way.
+// Usage: loom.RegisterScoped[*UsersController](l)
+func RegisterScoped[T any](l *Loom) {
+	register[T](l, true)
+}
+
+func register[T any](l *Loom, scoped bool) {
 	var zero T
 
 	controllerType := reflect.TypeOf(zero)
-	typeName := controllerType.Elem().Name()
+	typeName := controllerKey(controllerType)
 
 	if l.controllerRegistry == nil {
 		l.controllerRegistry = make(map[string]*controller)
@@ -59,27 +175,24 @@ func Register[T any](l *Loom) {
 	controllerInstance := reflect.New(structType).Interface()
 	controllerValue := reflect.ValueOf(controllerInstance).Elem()
 
-	// TODO: set individual fields on controllerValue based on any field type and which is present in l.Deps.Get
-	// only exported and maybe with a tag auto for example
-
 	if field, found := structType.FieldByName("Deps"); found {
 		ctrl := Controller{
 			Deps:        l.Deps,
-			FormDecoder: form.NewDecoder(),
-			Validator:   validator.New(validator.WithRequiredStructEnabled()),
+			FormDecoder: l.formDecoder,
+			Validator:   l.validator,
 		}
 
-		ctrl.Validator.RegisterTagNameFunc(func(fld reflect.StructField) string {
-			return strings.SplitN(fld.Tag.Get("form"), ",", 2)[0]
-		})
-
 		gField := controllerValue.Field(field.Index[0])
 		if gField.CanSet() {
 			gField.Set(reflect.ValueOf(ctrl))
 		}
 	}
 
-	if initMethod, hasInit := controllerType.MethodByName("Init"); hasInit {
+	// Unresolved dependencies are collected across all controllers and reported at once by Validate,
+	// Init is skipped since it would run against a partially injected controller
+	if errs := l.inject(controllerValue); len(errs) > 0 {
+		l.errs = append(l.errs, errs...)
+	} else if initMethod, hasInit := controllerType.MethodByName("Init"); hasInit {
 		results := initMethod.Func.Call([]reflect.Value{
 			reflect.ValueOf(controllerInstance),
 		})
@@ -89,30 +202,17 @@ func Register[T any](l *Loom) {
 		}
 	}
 
+	if _, exists := l.controllerRegistry[typeName]; !exists {
+		l.controllerOrder = append(l.controllerOrder, typeName)
+	}
+
 	l.controllerRegistry[typeName] = &controller{
 		Type:     controllerType,
 		Instance: controllerInstance,
+		scoped:   scoped,
 	}
 }
 
-// GET registers a new GET route for a path with a matching handler in the router
-// with optional route-level middleware.
-//
-// ctrlAction is the name of the controller action pair to be called (lowercased and without Controller suffix):
-// "users.index"
-func (l *Loom) GET(path string, ctrlAction string, m ...echo.MiddlewareFunc) *echo.Route {
-	return l.E.GET(path, l.handlerFor(ctrlAction), m...)
-}
-
-// POST registers a new POST route for a path with a matching handler in the router
-// with optional route-level middleware.
-//
-// ctrlAction is the name of the controller action pair to be called (lowercased and without Controller suffix):
-// "users.index"
-func (l *Loom) POST(path string, ctrlAction string, m ...echo.MiddlewareFunc) *echo.Route {
-	return l.E.POST(path, l.handlerFor(ctrlAction), m...)
-}
-
 func (l *Loom) handlerFor(ctrlAction string) echo.HandlerFunc {
 	parts := strings.Split(ctrlAction, ".")
 
@@ -120,11 +220,8 @@ func (l *Loom) handlerFor(ctrlAction string) echo.HandlerFunc {
 		panic(fmt.Sprintf("Invalid controller action format: %s. Expected 'Type.Method'", ctrlAction))
 	}
 
-	controllerTypeName := cases.Title(language.English).String(strings.ReplaceAll(parts[0], "_", " ")) + "Controller"
-	methodName := cases.Title(language.English).String(strings.ReplaceAll(parts[1], "_", " "))
-
-	controllerTypeName = strings.ReplaceAll(controllerTypeName, " ", "")
-	methodName = strings.ReplaceAll(methodName, " ", "")
+	controllerTypeName := controllerTypeName(parts[0])
+	methodName := actionMethodName(parts[1])
 
 	methodCall := l.getOrCreateMethodCall(controllerTypeName, methodName)
 
@@ -133,22 +230,47 @@ func (l *Loom) handlerFor(ctrlAction string) echo.HandlerFunc {
 		panic(fmt.Sprintf("Controller type %s not found in registry", controllerTypeName))
 	}
 
-	return func(c echo.Context) error {
-		// Call the target method
-		results := methodCall.method.Func.Call([]reflect.Value{
-			reflect.ValueOf(controller.Instance),
-			reflect.ValueOf(c),
-		})
+	invoke := func(instance any, c echo.Context) error {
+		return l.call(methodCall, instance, c)
+	}
 
-		// Return the result (should be error)
-		if len(results) > 0 && !results[0].IsNil() {
-			return results[0].Interface().(error)
+	// prefer the generated binding over reflection when there is one
+	if binding, ok := controller.bindings[methodName]; ok {
+		invoke = binding
+	}
+
+	if controller.scoped {
+		return func(c echo.Context) error {
+			instance := controller.newInstance()
+
+			// filters are bound to the instance so they are collected per request as well
+			return filtersFor(instance, parts[1]).run(c, func() error {
+				return invoke(instance, c)
+			})
 		}
+	}
 
-		return nil
+	filters := filtersFor(controller.Instance, parts[1])
+
+	return func(c echo.Context) error {
+		return filters.run(c, func() error {
+			return invoke(controller.Instance, c)
+		})
 	}
 }
 
+// controllerTypeName converts the controller part of a ctrlAction to the registered type name:
+// "user_profiles" -> "UserProfilesController"
+func controllerTypeName(name string) string {
+	return strings.ReplaceAll(cases.Title(language.English).String(strings.ReplaceAll(name, "_", " ")), " ", "") + "Controller"
+}
+
+// actionMethodName converts the action part of a ctrlAction to the controller method name:
+// "not_found" -> "NotFound"
+func actionMethodName(action string) string {
+	return strings.ReplaceAll(cases.Title(language.English).String(strings.ReplaceAll(action, "_", " ")), " ", "")
+}
+
 func (l *Loom) getOrCreateMethodCall(controllerTypeName, methodName string) *methodCall {
 	if l.methodRegistry == nil {
 		l.methodRegistry = make(map[string]*methodCall)
@@ -170,10 +292,7 @@ func (l *Loom) getOrCreateMethodCall(controllerTypeName, methodName string) *met
 		panic(fmt.Sprintf("Method %s not found on controller type %s", methodName, controllerTypeName))
 	}
 
-	methodCall := &methodCall{
-		controllerInstance: controllerType.Instance,
-		method:             method,
-	}
+	methodCall := newMethodCall(controllerType.Instance, method)
 
 	l.methodRegistry[methodKey] = methodCall
 
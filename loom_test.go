@@ -104,6 +104,67 @@ func TestLoom_GET(t *testing.T) {
 	}
 }
 
+func TestLoom_Verbs(t *testing.T) {
+	deps := NewDeps()
+	loom := New(deps)
+
+	Register[*testController](loom)
+
+	tests := []struct {
+		method   string
+		register func(path string, ctrlAction string, m ...echo.MiddlewareFunc) *echo.Route
+	}{
+		{http.MethodGet, loom.GET},
+		{http.MethodPost, loom.POST},
+		{http.MethodPut, loom.PUT},
+		{http.MethodPatch, loom.PATCH},
+		{http.MethodDelete, loom.DELETE},
+		{http.MethodHead, loom.HEAD},
+		{http.MethodOptions, loom.OPTIONS},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			route := tt.register("/verbs", "test.index")
+
+			if route.Method != tt.method {
+				t.Errorf("Route method = %v, want %v", route.Method, tt.method)
+			}
+
+			req := httptest.NewRequest(tt.method, "/verbs", nil)
+			rec := httptest.NewRecorder()
+			loom.E.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Errorf("%s status = %v, want %v", tt.method, rec.Code, http.StatusOK)
+			}
+		})
+	}
+}
+
+func TestLoom_Any(t *testing.T) {
+	deps := NewDeps()
+	loom := New(deps)
+
+	Register[*testController](loom)
+
+	routes := loom.Any("/any", "test.show")
+
+	if len(routes) != len(anyMethods) {
+		t.Errorf("Any() registered %d routes, want %d", len(routes), len(anyMethods))
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		req := httptest.NewRequest(method, "/any", nil)
+		rec := httptest.NewRecorder()
+		loom.E.ServeHTTP(rec, req)
+
+		if strings.TrimSpace(rec.Body.String()) != "show" {
+			t.Errorf("%s body = %v, want %v", method, rec.Body.String(), "show")
+		}
+	}
+}
+
 func TestHandlerFor_ValidAction(t *testing.T) {
 	deps := NewDeps()
 	loom := New(deps)
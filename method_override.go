@@ -0,0 +1,31 @@
+package loom
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// MethodOverrideField is the name of the hidden form field carrying the overridden method
+const MethodOverrideField = "_method"
+
+// MethodOverrideMiddleware lets plain HTML forms submit PUT, PATCH and DELETE requests.
+// A POST request with a hidden _method field is routed as if it was sent with that method.
+// Only url encoded forms are looked at, so multipart bodies are not parsed before the route
+// middleware, eg. BodyLimit, had a chance to run.
+// It has to run before routing: l.E.Pre(loom.MethodOverrideMiddleware)
+func MethodOverrideMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+
+		if req.Method == http.MethodPost && strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEApplicationForm) {
+			switch method := strings.ToUpper(c.FormValue(MethodOverrideField)); method {
+			case http.MethodPut, http.MethodPatch, http.MethodDelete:
+				req.Method = method
+			}
+		}
+
+		return next(c)
+	}
+}
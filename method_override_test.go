@@ -0,0 +1,82 @@
+package loom
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestMethodOverrideMiddleware(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		override    string
+		want        string
+	}{
+		{"post with put", http.MethodPost, echo.MIMEApplicationForm, "put", http.MethodPut},
+		{"post with patch", http.MethodPost, echo.MIMEApplicationForm, "PATCH", http.MethodPatch},
+		{"post with delete", http.MethodPost, echo.MIMEApplicationForm, "DELETE", http.MethodDelete},
+		{"post without override", http.MethodPost, echo.MIMEApplicationForm, "", http.MethodPost},
+		{"post with unsupported method", http.MethodPost, echo.MIMEApplicationForm, "TRACE", http.MethodPost},
+		{"get is never overridden", http.MethodGet, echo.MIMEApplicationForm, "DELETE", http.MethodGet},
+		{"multipart body is not parsed", http.MethodPost, echo.MIMEMultipartForm, "DELETE", http.MethodPost},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+
+			form := url.Values{}
+			if tt.override != "" {
+				form.Set(MethodOverrideField, tt.override)
+			}
+
+			req := httptest.NewRequest(tt.method, "/", strings.NewReader(form.Encode()))
+			req.Header.Set(echo.HeaderContentType, tt.contentType)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			var got string
+
+			handler := MethodOverrideMiddleware(func(c echo.Context) error {
+				got = c.Request().Method
+				return nil
+			})
+
+			if err := handler(c); err != nil {
+				t.Errorf("MethodOverrideMiddleware() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("method = %v, want %v", got, tt.want)
+			}
+
+			if tt.contentType != echo.MIMEApplicationForm && req.Form != nil {
+				t.Error("MethodOverrideMiddleware() parsed the body")
+			}
+		})
+	}
+}
+
+func TestMethodOverrideMiddleware_Routing(t *testing.T) {
+	loom := New(NewDeps())
+
+	Register[*notesController](loom)
+
+	loom.E.Pre(MethodOverrideMiddleware)
+	loom.Resources("/notes", "notes")
+
+	req := httptest.NewRequest(http.MethodPost, "/notes/5", strings.NewReader("_method=PUT"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec := httptest.NewRecorder()
+	loom.E.ServeHTTP(rec, req)
+
+	if strings.TrimSpace(rec.Body.String()) != "update 5" {
+		t.Errorf("body = %v, want %v", rec.Body.String(), "update 5")
+	}
+}
@@ -0,0 +1,171 @@
+package loom
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Module packages a feature of the application, eg. billing, so it can be added with Use.
+// Register adds the module's services to Deps, registers its controllers, routes and middleware.
+//
+//	type Module struct{}
+//
+//	func (Module) Name() string { return "billing" }
+//
+//	func (Module) Register(l *loom.Loom) error {
+//		loom.Provide(l.Deps, NewInvoices)
+//		loom.Register[*InvoicesController](l)
+//		l.Resources("/invoices", "invoices")
+//		return nil
+//	}
+type Module interface {
+	Name() string
+	Register(l *Loom) error
+}
+
+// ModuleDependencies is implemented by modules that need other modules to be registered first
+type ModuleDependencies interface {
+	// DependsOn returns the names of the modules this module depends on
+	DependsOn() []string
+}
+
+// ModuleMigrations is implemented by modules shipping their own database migrations.
+// `loom db migrate` runs them after the application migrations, in module order,
+// tracking each module's versions in its own schema_migrations_<name> table.
+type ModuleMigrations interface {
+	// Migrations returns the migrations directory relative to the application root:
+	// "internal/billing/migrations"
+	Migrations() string
+}
+
+// ModuleInfo describes a module added with Use
+type ModuleInfo struct {
+	Name       string   `json:"name"`
+	DependsOn  []string `json:"depends_on"`
+	Migrations string   `json:"migrations,omitempty"`
+}
+
+// Use registers the modules ordered by their declared dependencies (see ModuleDependencies),
+// modules without dependencies between them are registered in the order they were passed.
+// Dependencies can be modules passed to the same call or added by a previous one.
+// Usage: check(l.Use(billing.Module{}, accounts.Module{}))
+func (l *Loom) Use(modules ...Module) error {
+	ordered, err := l.orderModules(modules)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range ordered {
+		if err := m.Register(l); err != nil {
+			return fmt.Errorf("failed to register module %s: %w", m.Name(), err)
+		}
+
+		info := ModuleInfo{
+			Name:      m.Name(),
+			DependsOn: moduleDependencies(m),
+		}
+
+		if mm, ok := m.(ModuleMigrations); ok {
+			info.Migrations = mm.Migrations()
+		}
+
+		l.modules = append(l.modules, info)
+	}
+
+	return nil
+}
+
+// Modules returns the modules added with Use in registration order
+func (l *Loom) Modules() []ModuleInfo {
+	modules := make([]ModuleInfo, len(l.modules))
+	copy(modules, l.modules)
+
+	return modules
+}
+
+func moduleDependencies(m Module) []string {
+	deps := []string{}
+
+	if md, ok := m.(ModuleDependencies); ok {
+		deps = append(deps, md.DependsOn()...)
+	}
+
+	return deps
+}
+
+// orderModules sorts the modules topologically, keeping the given order where possible
+func (l *Loom) orderModules(modules []Module) ([]Module, error) {
+	registered := make(map[string]bool, len(l.modules))
+	for _, info := range l.modules {
+		registered[info.Name] = true
+	}
+
+	byName := make(map[string]Module, len(modules))
+
+	for _, m := range modules {
+		name := m.Name()
+
+		if registered[name] || byName[name] != nil {
+			return nil, fmt.Errorf("module %s is already registered", name)
+		}
+
+		byName[name] = m
+	}
+
+	for _, m := range modules {
+		for _, dep := range moduleDependencies(m) {
+			if !registered[dep] && byName[dep] == nil {
+				return nil, fmt.Errorf("module %s depends on unknown module %s", m.Name(), dep)
+			}
+		}
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	var (
+		ordered []Module
+		state   = make(map[string]int, len(modules))
+		visit   func(m Module, path []string) error
+	)
+
+	visit = func(m Module, path []string) error {
+		name := m.Name()
+		path = append(path, name)
+
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("module dependency cycle: %s", strings.Join(path[slices.Index(path, name):], " -> "))
+		}
+
+		state[name] = visiting
+
+		for _, dep := range moduleDependencies(m) {
+			if registered[dep] {
+				continue
+			}
+
+			if err := visit(byName[dep], path); err != nil {
+				return err
+			}
+		}
+
+		state[name] = visited
+		ordered = append(ordered, m)
+
+		return nil
+	}
+
+	for _, m := range modules {
+		if err := visit(m, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
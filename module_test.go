@@ -0,0 +1,125 @@
+package loom
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type testModule struct {
+	name      string
+	dependsOn []string
+	register  func(l *Loom) error
+	order     *[]string
+}
+
+func (m testModule) Name() string { return m.name }
+
+func (m testModule) DependsOn() []string { return m.dependsOn }
+
+func (m testModule) Register(l *Loom) error {
+	*m.order = append(*m.order, m.name)
+
+	if m.register != nil {
+		return m.register(l)
+	}
+
+	return nil
+}
+
+type migratingModule struct {
+	testModule
+}
+
+func (m migratingModule) Migrations() string { return "internal/" + m.name + "/migrations" }
+
+func TestLoom_Use(t *testing.T) {
+	l := New(NewDeps())
+
+	var order []string
+
+	err := l.Use(
+		testModule{name: "billing", dependsOn: []string{"accounts", "mail"}, order: &order},
+		testModule{name: "mail", order: &order, register: func(l *Loom) error {
+			Add(l.Deps, &mailer{})
+			return nil
+		}},
+		migratingModule{testModule{name: "accounts", dependsOn: []string{"mail"}, order: &order, register: func(l *Loom) error {
+			Register[*notesController](l)
+			l.Resources("/accounts", "notes", Only("index"))
+			return nil
+		}}},
+	)
+	if err != nil {
+		t.Fatalf("Use() error = %v", err)
+	}
+
+	if got := strings.Join(order, ","); got != "mail,accounts,billing" {
+		t.Errorf("modules registered in order %v, want mail,accounts,billing", got)
+	}
+
+	if !Has[*mailer](l.Deps) {
+		t.Error("module services were not added to Deps")
+	}
+
+	if rec := serve(l, http.MethodGet, "/accounts"); rec.Code != http.StatusOK {
+		t.Errorf("module route status = %v, want %v", rec.Code, http.StatusOK)
+	}
+
+	modules := l.Modules()
+	if len(modules) != 3 || modules[1].Migrations != "internal/accounts/migrations" {
+		t.Errorf("Modules() = %+v", modules)
+	}
+
+	// modules added by a previous call satisfy dependencies
+	if err := l.Use(testModule{name: "reports", dependsOn: []string{"billing"}, order: &order}); err != nil {
+		t.Errorf("Use() error = %v", err)
+	}
+}
+
+func TestLoom_UseErrors(t *testing.T) {
+	var order []string
+
+	tests := []struct {
+		name    string
+		modules []Module
+		want    string
+	}{
+		{
+			name:    "unknown dependency",
+			modules: []Module{testModule{name: "billing", dependsOn: []string{"accounts"}, order: &order}},
+			want:    "module billing depends on unknown module accounts",
+		},
+		{
+			name: "cycle",
+			modules: []Module{
+				testModule{name: "a", dependsOn: []string{"b"}, order: &order},
+				testModule{name: "b", dependsOn: []string{"c"}, order: &order},
+				testModule{name: "c", dependsOn: []string{"a"}, order: &order},
+			},
+			want: "module dependency cycle: a -> b -> c -> a",
+		},
+		{
+			name:    "duplicate",
+			modules: []Module{testModule{name: "a", order: &order}, testModule{name: "a", order: &order}},
+			want:    "module a is already registered",
+		},
+		{
+			name: "register error",
+			modules: []Module{testModule{name: "a", order: &order, register: func(l *Loom) error {
+				return errors.New("boom")
+			}}},
+			want: "failed to register module a: boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New(NewDeps()).Use(tt.modules...)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Use() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package loom
+
+// TestingT is the part of testing.TB used by Override
+type TestingT interface {
+	Helper()
+	Cleanup(func())
+}
+
+// Override replaces a dependency with a fake for the duration of a test, the original
+// registration is restored (or the fake removed) when the test finishes. Controllers get their
+// dependencies injected when they are registered, so override before registering them,
+// or override in a Child to leave a shared container untouched.
+// Usage: loom.Override[Mailer](t, deps, &fakeMailer{})
+func Override[T any](t TestingT, d *Deps, fake T) {
+	t.Helper()
+
+	override(t, d, depKey{t: getType[T](), label: ""}, fake)
+}
+
+// OverrideWithLabel replaces the dependency registered with a label for the duration of a test
+// Usage: loom.OverrideWithLabel[*sql.DB](t, deps, testDB, "replica")
+func OverrideWithLabel[T any](t TestingT, d *Deps, fake T, label string) {
+	t.Helper()
+
+	override(t, d, depKey{t: getType[T](), label: label}, fake)
+}
+
+func override(t TestingT, d *Deps, key depKey, fake any) {
+	d.mu.RLock()
+	previous, existed := d.services[key.t][key.label]
+	registration := d.registrations[key]
+	d.mu.RUnlock()
+
+	d.set(key.t, key.label, fake, callerSource(2))
+
+	t.Cleanup(func() {
+		if !existed {
+			d.remove(key)
+			return
+		}
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		if d.services[key.t] == nil {
+			d.services[key.t] = make(map[string]any)
+		}
+
+		if _, exists := d.services[key.t][key.label]; !exists {
+			d.order = append(d.order, key)
+		}
+
+		d.services[key.t][key.label] = previous
+		d.registrations[key] = registration
+	})
+}
@@ -0,0 +1,136 @@
+package loom
+
+import (
+	"strings"
+	"testing"
+)
+
+type clock interface {
+	Now() string
+}
+
+type realClock struct{}
+
+func (realClock) Now() string { return "now" }
+
+type fakeClock struct{}
+
+func (fakeClock) Now() string { return "2026-01-01" }
+
+func TestDeps_Child(t *testing.T) {
+	parent := NewDeps()
+
+	Add[clock](parent, realClock{})
+	AddWithLabel(parent, &mailer{}, "smtp")
+
+	Provide(parent, func(d *Deps) (*repo, error) {
+		c, err := Get[clock](d)
+		return &repo{id: len(c.Now())}, err
+	})
+
+	child := parent.Child()
+
+	Add[clock](child, fakeClock{})
+
+	if now := MustGet[clock](child).Now(); now != "2026-01-01" {
+		t.Errorf("child clock = %v, want the shadowing fake", now)
+	}
+
+	if now := MustGet[clock](parent).Now(); now != "now" {
+		t.Errorf("parent clock = %v, the child must not change the parent", now)
+	}
+
+	if MustGetWithLabel[*mailer](child, "smtp") != MustGetWithLabel[*mailer](parent, "smtp") {
+		t.Error("child does not inherit the parent registrations")
+	}
+
+	// factories of the parent resolve from the child
+	if r := MustGet[*repo](child); r.id != len("2026-01-01") {
+		t.Errorf("repo.id = %v, want it built from the child clock", r.id)
+	}
+
+	if Count[clock](child) != 1 || !Has[*repo](child) {
+		t.Error("Count() and Has() must see the parent registrations")
+	}
+
+	if _, err := GetWithLabel[*mailer](child, "sendgrid"); err == nil || !strings.Contains(err.Error(), "with label 'sendgrid'") {
+		t.Errorf("GetWithLabel() error = %v", err)
+	}
+}
+
+func TestDeps_Child_Singletons(t *testing.T) {
+	parent := NewDeps()
+
+	Add[clock](parent, realClock{})
+
+	Provide(parent, func(d *Deps) (*repo, error) {
+		c, err := Get[clock](d)
+		return &repo{id: len(c.Now())}, err
+	})
+
+	// depends on the clock through the repo
+	Provide(parent, func(d *Deps) (*repoController, error) {
+		r, err := Get[*repo](d)
+		return &repoController{Repo: r}, err
+	})
+
+	Provide(parent, func(d *Deps) (*mailer, error) {
+		return &mailer{}, nil
+	})
+
+	parentRepo := MustGet[*repo](parent)
+	parentMailer := MustGet[*mailer](parent)
+
+	child := parent.Child()
+
+	Add[clock](child, fakeClock{})
+
+	ctrl := MustGet[*repoController](child)
+	if ctrl.Repo.id != len("2026-01-01") {
+		t.Errorf("repo.id = %v, want the repo built again from the child clock", ctrl.Repo.id)
+	}
+
+	if MustGet[*repo](child) != ctrl.Repo || MustGet[*repoController](child) != ctrl {
+		t.Error("singletons built by the child are not kept in the child")
+	}
+
+	if MustGet[*repo](parent) != parentRepo || parentRepo.id != len("now") {
+		t.Error("the child must not change the singletons of the parent")
+	}
+
+	if MustGet[*repoController](parent).Repo != parentRepo {
+		t.Error("the parent built its singleton from the child registrations")
+	}
+
+	if MustGet[*mailer](child) != parentMailer {
+		t.Error("singletons not depending on shadowed services are shared with the parent")
+	}
+}
+
+func TestOverride(t *testing.T) {
+	deps := NewDeps()
+
+	Add[clock](deps, realClock{})
+
+	t.Run("with fake", func(t *testing.T) {
+		Override[clock](t, deps, fakeClock{})
+		OverrideWithLabel(t, deps, &mailer{}, "smtp")
+
+		if now := MustGet[clock](deps).Now(); now != "2026-01-01" {
+			t.Errorf("clock = %v, want the fake", now)
+		}
+
+		graph := deps.Graph()
+		if !strings.HasPrefix(graph[0].Source, "override_test.go:") {
+			t.Errorf("Source = %v, want the Override call site", graph[0].Source)
+		}
+	})
+
+	if now := MustGet[clock](deps).Now(); now != "now" {
+		t.Errorf("clock = %v, want the original restored after the test", now)
+	}
+
+	if HasWithLabel[*mailer](deps, "smtp") {
+		t.Error("fake without an original registration was not removed after the test")
+	}
+}
@@ -0,0 +1,342 @@
+package loom
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Lifetime controls how often the factory of a provided dependency is called
+type Lifetime int
+
+const (
+	// Singleton dependencies are built once, the first time they are needed
+	Singleton Lifetime = iota
+
+	// Transient dependencies are built every time they are resolved
+	Transient
+
+	// Scoped dependencies are built once per request and resolved with Resolve
+	Scoped
+)
+
+func (l Lifetime) String() string {
+	switch l {
+	case Singleton:
+		return "singleton"
+	case Transient:
+		return "transient"
+	case Scoped:
+		return "scoped"
+	}
+
+	return fmt.Sprintf("Lifetime(%d)", int(l))
+}
+
+// ErrDependencyCycle is returned when resolving a dependency ends up needing itself
+var ErrDependencyCycle = errors.New("dependency cycle")
+
+// Factory builds a dependency, it resolves what it needs from the Deps it is given
+type Factory[T any] func(d *Deps) (T, error)
+
+// ProvideOption configures a provided dependency
+type ProvideOption func(*provideOptions)
+
+type provideOptions struct {
+	label string
+}
+
+// WithLabel registers the provided dependency under a label
+// Usage: loom.Provide(deps, newReplicaDB, loom.WithLabel("replica"))
+func WithLabel(label string) ProvideOption {
+	return func(o *provideOptions) {
+		o.label = label
+	}
+}
+
+// Provide registers a factory building a singleton the first time it is needed
+// Usage: loom.Provide(deps, func(d *loom.Deps) (*Mailer, error) { return NewMailer(loom.MustGet[*Config](d)), nil })
+func Provide[T any](d *Deps, factory Factory[T], opts ...ProvideOption) {
+	provide(d, Singleton, factory, opts, callerSource(1))
+}
+
+// ProvideTransient registers a factory building a new instance every time the dependency is resolved
+func ProvideTransient[T any](d *Deps, factory Factory[T], opts ...ProvideOption) {
+	provide(d, Transient, factory, opts, callerSource(1))
+}
+
+// ProvideScoped registers a factory building one instance per request, scoped dependencies
+// are resolved from the request with Resolve and closed at the end of it if they are an io.Closer.
+// They can't be resolved by singletons or injected into controllers.
+func ProvideScoped[T any](d *Deps, factory Factory[T], opts ...ProvideOption) {
+	provide(d, Scoped, factory, opts, callerSource(1))
+}
+
+func provide[T any](d *Deps, lifetime Lifetime, factory Factory[T], opts []ProvideOption, source string) {
+	var o provideOptions
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	d.set(getType[T](), o.label, &provider{
+		lifetime: lifetime,
+		factory: func(d *Deps) (any, error) {
+			return factory(d)
+		},
+	}, source)
+}
+
+// Resolve retrieves a dependency for the request, it is the only way to get Scoped dependencies
+// Usage: uow, err := loom.Resolve[*UnitOfWork](c)
+func Resolve[T any](c echo.Context) (T, error) {
+	return ResolveWithLabel[T](c, "")
+}
+
+// ResolveWithLabel retrieves a dependency with the specified label for the request
+func ResolveWithLabel[T any](c echo.Context, label string) (T, error) {
+	l := fromContext(c.Request().Context())
+
+	d := &Deps{
+		registry: l.Deps.registry,
+		scope:    requestScope(c),
+	}
+
+	return GetWithLabel[T](d, label)
+}
+
+// provider is a dependency registered with a factory
+type provider struct {
+	lifetime Lifetime
+	factory  func(d *Deps) (any, error)
+
+	mu    sync.Mutex
+	value any
+	built bool
+
+	// deps are the dependencies the singleton is built from, directly or through other factories
+	deps []depKey
+}
+
+// resolve builds or returns the instance of the provider registered in owner for d.
+// Factories resolve their dependencies from d, so the registrations of a child reach
+// the factories of its parents. A singleton of a parent depending on a dependency the
+// child shadows is kept in the child, the parent keeps its own.
+func (p *provider) resolve(d *Deps, owner *registry, key depKey) (any, error) {
+	if i := slices.Index(d.resolving, key); i >= 0 {
+		return nil, cycleError(append(slices.Clone(d.resolving[i:]), key))
+	}
+
+	switch p.lifetime {
+	case Transient:
+		value, _, err := p.build(d, key, d.scope)
+		return value, err
+	case Scoped:
+		if d.scope == nil {
+			return nil, fmt.Errorf("scoped service %v can only be resolved for a request (see Resolve)", key)
+		}
+
+		return d.scope.get(p, func() (any, error) {
+			value, _, err := p.build(d, key, d.scope)
+			return value, err
+		})
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if value, ok := d.singleton(p); ok {
+		d.record(p.deps...)
+		return value, nil
+	}
+
+	if p.built && !d.shadows(owner, p.deps) {
+		d.record(p.deps...)
+		return p.value, nil
+	}
+
+	// singletons outlive requests so they must not capture scoped dependencies
+	value, deps, err := p.build(d, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	p.deps = deps
+
+	if d.shadows(owner, deps) {
+		d.setSingleton(p, value)
+	} else {
+		p.value, p.built = value, true
+	}
+
+	return value, nil
+}
+
+// build calls the factory, it returns the dependencies the factory resolved along with the instance
+func (p *provider) build(d *Deps, key depKey, s *scope) (any, []depKey, error) {
+	var deps []depKey
+
+	view := &Deps{
+		registry:  d.registry,
+		resolving: append(slices.Clip(d.resolving), key),
+		scope:     s,
+		resolved:  &deps,
+	}
+
+	value, err := p.factory(view)
+
+	d.record(deps...)
+
+	if err != nil {
+		// cycle errors already carry the full chain
+		if errors.Is(err, ErrDependencyCycle) {
+			return nil, nil, err
+		}
+
+		return nil, nil, fmt.Errorf("failed to build %v: %w", key, err)
+	}
+
+	return value, deps, nil
+}
+
+// singleton returns the value of a singleton provider once it was built
+func (p *provider) singleton() (any, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.value, p.lifetime == Singleton && p.built
+}
+
+func cycleError(chain []depKey) error {
+	names := make([]string, len(chain))
+	for i, key := range chain {
+		names[i] = key.String()
+	}
+
+	return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(names, " -> "))
+}
+
+// scopeKey is the echo context key holding the request scope
+const scopeKey = "loom.scope"
+
+// scope holds the Scoped dependencies built for a request
+type scope struct {
+	mu        sync.Mutex
+	instances map[*provider]any
+	order     []any
+}
+
+func newScope() *scope {
+	return &scope{instances: make(map[*provider]any)}
+}
+
+func requestScope(c echo.Context) *scope {
+	s, ok := c.Get(scopeKey).(*scope)
+	if !ok {
+		s = newScope()
+		c.Set(scopeKey, s)
+	}
+
+	return s
+}
+
+// get returns the instance of the provider built for the scope, the lock is not held
+// while building so scoped factories can resolve other scoped dependencies
+func (s *scope) get(p *provider, build func() (any, error)) (any, error) {
+	s.mu.Lock()
+	value, ok := s.instances[p]
+	s.mu.Unlock()
+
+	if ok {
+		return value, nil
+	}
+
+	value, err := build()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.instances[p]; ok {
+		return existing, nil
+	}
+
+	s.instances[p] = value
+	s.order = append(s.order, value)
+
+	return value, nil
+}
+
+// close closes the io.Closer instances of the scope in reverse order
+func (s *scope) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+
+	for _, value := range slices.Backward(s.order) {
+		if closer, ok := value.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("failed to close %T: %w", value, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate builds every dependency registered with a factory, so missing dependencies,
+// cycles and failing factories are reported at startup instead of on the first request (see Loom.ValidateDeps).
+// Singletons stay built, transient and scoped instances are built in a throwaway scope.
+func (d *Deps) Validate() error {
+	d.mu.RLock()
+
+	var keys []depKey
+	for _, key := range d.order {
+		if _, ok := d.services[key.t][key.label].(*provider); ok {
+			keys = append(keys, key)
+		}
+	}
+
+	d.mu.RUnlock()
+
+	s := newScope()
+
+	view := &Deps{registry: d.registry, scope: s}
+
+	var errs []error
+
+	for _, key := range keys {
+		value, err := view.get(key.t, key.label)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if p, ok := d.provider(key); ok && p.lifetime == Transient {
+			if closer, ok := value.(io.Closer); ok {
+				errs = append(errs, closer.Close())
+			}
+		}
+	}
+
+	errs = append(errs, s.close())
+
+	return errors.Join(errs...)
+}
+
+func (d *Deps) provider(key depKey) (*provider, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	p, ok := d.services[key.t][key.label].(*provider)
+
+	return p, ok
+}
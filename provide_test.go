@@ -0,0 +1,236 @@
+package loom
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+type repo struct {
+	id int
+}
+
+type unitOfWork struct {
+	closed bool
+}
+
+func (u *unitOfWork) Close() error {
+	u.closed = true
+	return nil
+}
+
+type cycleA struct{}
+type cycleB struct{}
+type cycleC struct{}
+
+func TestProvide_Singleton(t *testing.T) {
+	deps := NewDeps()
+
+	calls := 0
+
+	Provide(deps, func(d *Deps) (*repo, error) {
+		calls++
+		return &repo{id: calls}, nil
+	})
+
+	if calls != 0 {
+		t.Fatal("Provide() called the factory before the dependency was needed")
+	}
+
+	first := MustGet[*repo](deps)
+	second := MustGet[*repo](deps)
+
+	if calls != 1 || first != second {
+		t.Errorf("singleton factory called %d times, want 1", calls)
+	}
+}
+
+func TestProvide_Transient(t *testing.T) {
+	deps := NewDeps()
+
+	calls := 0
+
+	ProvideTransient(deps, func(d *Deps) (*repo, error) {
+		calls++
+		return &repo{id: calls}, nil
+	}, WithLabel("fresh"))
+
+	first := MustGetWithLabel[*repo](deps, "fresh")
+	second := MustGetWithLabel[*repo](deps, "fresh")
+
+	if first == second || calls != 2 {
+		t.Errorf("transient factory called %d times, want 2", calls)
+	}
+}
+
+func TestProvide_Dependencies(t *testing.T) {
+	deps := NewDeps()
+
+	Add(deps, "dsn")
+
+	Provide(deps, func(d *Deps) (*repo, error) {
+		dsn, err := Get[string](d)
+		if err != nil {
+			return nil, err
+		}
+
+		return &repo{id: len(dsn)}, nil
+	})
+
+	if r := MustGet[*repo](deps); r.id != 3 {
+		t.Errorf("repo.id = %v, want 3", r.id)
+	}
+}
+
+func TestProvide_FactoryError(t *testing.T) {
+	deps := NewDeps()
+
+	Provide(deps, func(d *Deps) (*repo, error) {
+		return nil, errors.New("connection refused")
+	})
+
+	_, err := Get[*repo](deps)
+	if err == nil || !strings.Contains(err.Error(), "failed to build *loom.repo: connection refused") {
+		t.Errorf("Get() error = %v", err)
+	}
+}
+
+func TestProvide_Cycle(t *testing.T) {
+	deps := NewDeps()
+
+	Provide(deps, func(d *Deps) (*cycleA, error) {
+		_, err := Get[*cycleB](d)
+		return &cycleA{}, err
+	})
+
+	Provide(deps, func(d *Deps) (*cycleB, error) {
+		_, err := GetWithLabel[*cycleC](d, "c")
+		return &cycleB{}, err
+	})
+
+	Provide(deps, func(d *Deps) (*cycleC, error) {
+		_, err := Get[*cycleA](d)
+		return &cycleC{}, err
+	}, WithLabel("c"))
+
+	_, err := Get[*cycleA](deps)
+
+	if !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("Get() error = %v, want ErrDependencyCycle", err)
+	}
+
+	want := "dependency cycle: *loom.cycleA -> *loom.cycleB -> *loom.cycleC[c] -> *loom.cycleA"
+	if err.Error() != want {
+		t.Errorf("Get() error = %v, want %v", err, want)
+	}
+}
+
+func TestProvide_Scoped(t *testing.T) {
+	deps := NewDeps()
+
+	var built []*unitOfWork
+
+	ProvideScoped(deps, func(d *Deps) (*unitOfWork, error) {
+		uow := &unitOfWork{}
+		built = append(built, uow)
+
+		return uow, nil
+	})
+
+	l := New(deps)
+
+	l.E.GET("/", func(c echo.Context) error {
+		first, err := Resolve[*unitOfWork](c)
+		if err != nil {
+			return err
+		}
+
+		second, err := Resolve[*unitOfWork](c)
+		if err != nil {
+			return err
+		}
+
+		if first != second {
+			return errors.New("scoped dependency resolved twice in one request")
+		}
+
+		return c.NoContent(http.StatusOK)
+	})
+
+	for range 2 {
+		if rec := serve(l, http.MethodGet, "/"); rec.Code != http.StatusOK {
+			t.Fatalf("status = %v, body = %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	if len(built) != 2 || built[0] == built[1] {
+		t.Fatalf("scoped factory built %d instances, want one per request", len(built))
+	}
+
+	if !built[0].closed || !built[1].closed {
+		t.Error("scoped dependencies were not closed at the end of the request")
+	}
+
+	if _, err := Get[*unitOfWork](deps); err == nil {
+		t.Error("Get() resolved a scoped dependency outside of a request")
+	}
+}
+
+func TestProvide_SingletonCannotCaptureScoped(t *testing.T) {
+	deps := NewDeps()
+
+	ProvideScoped(deps, func(d *Deps) (*unitOfWork, error) {
+		return &unitOfWork{}, nil
+	})
+
+	Provide(deps, func(d *Deps) (*repo, error) {
+		_, err := Get[*unitOfWork](d)
+		return &repo{}, err
+	})
+
+	if err := deps.Validate(); err == nil {
+		t.Error("Validate() expected error for singleton depending on scoped dependency")
+	}
+}
+
+type repoController struct {
+	Controller
+
+	Repo *repo `loom:"inject"`
+}
+
+func TestLoom_ValidateDeps(t *testing.T) {
+	deps := NewDeps()
+
+	Provide(deps, func(d *Deps) (*repo, error) {
+		return &repo{id: 7}, nil
+	})
+
+	Provide(deps, func(d *Deps) (*cycleA, error) {
+		_, err := Get[*cycleB](d)
+		return &cycleA{}, err
+	})
+
+	l := New(deps)
+
+	Register[*repoController](l)
+
+	if id := l.controllerRegistry["RepoController"].Instance.(*repoController).Repo.id; id != 7 {
+		t.Errorf("injected repo.id = %v, want 7", id)
+	}
+
+	// factories are only built on demand unless ValidateDeps is set
+	if err := l.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	l.ValidateDeps = true
+
+	err := l.Validate()
+	if err == nil || !strings.Contains(err.Error(), "no service registered for type *loom.cycleB") {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
@@ -1,6 +1,11 @@
 package loom
 
-import "github.com/a-h/templ"
+import (
+	"context"
+
+	"github.com/a-h/templ"
+	"github.com/labstack/echo/v4"
+)
 
 type RenderOptions struct {
 	Title  string
@@ -22,3 +27,34 @@ func WithLayout(layout func(_ templ.Component) templ.Component) RenderOption {
 		return options
 	}
 }
+
+// LayoutKey is the request context key holding the default layout of a route group
+type LayoutKey struct{}
+
+// LayoutFrom returns the default layout set for the route group that is handling the request
+func LayoutFrom(ctx context.Context) (func(component templ.Component) templ.Component, bool) {
+	layout, ok := ctx.Value(LayoutKey{}).(func(component templ.Component) templ.Component)
+
+	return layout, ok
+}
+
+func layoutMiddleware(layout func(component templ.Component) templ.Component) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := context.WithValue(c.Request().Context(), LayoutKey{}, layout)
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
+
+// RenderFunc renders a templ component as the response of a request
+type RenderFunc func(c echo.Context, component templ.Component, opts ...RenderOption) error
+
+// renderComponent is the default Loom.Renderer, it renders the bare component
+func renderComponent(c echo.Context, component templ.Component, _ ...RenderOption) error {
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
+
+	return component.Render(c.Request().Context(), c.Response().Writer)
+}
@@ -0,0 +1,143 @@
+package loom
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// resourceAction describes one of the conventional RESTful resource routes
+type resourceAction struct {
+	name   string
+	method string
+	suffix string
+}
+
+// resourceActions are registered in this order so that static segments such as
+// /new are declared before the /:id routes
+var resourceActions = []resourceAction{
+	{name: "index", method: http.MethodGet, suffix: ""},
+	{name: "new", method: http.MethodGet, suffix: "/new"},
+	{name: "create", method: http.MethodPost, suffix: ""},
+	{name: "show", method: http.MethodGet, suffix: "/:id"},
+	{name: "edit", method: http.MethodGet, suffix: "/:id/edit"},
+	{name: "update", method: http.MethodPut, suffix: "/:id"},
+	{name: "update", method: http.MethodPatch, suffix: "/:id"},
+	{name: "destroy", method: http.MethodDelete, suffix: "/:id"},
+}
+
+// ActionOption narrows down the set of controller actions something applies to
+type ActionOption func(*actionSet)
+
+// Only limits the actions to the ones listed
+// Usage: loom.Only("index", "show")
+func Only(actions ...string) ActionOption {
+	return func(s *actionSet) {
+		if s.only == nil {
+			s.only = make(map[string]bool)
+		}
+
+		for _, action := range actions {
+			s.only[action] = true
+		}
+	}
+}
+
+// Except excludes the listed actions
+// Usage: loom.Except("destroy")
+func Except(actions ...string) ActionOption {
+	return func(s *actionSet) {
+		if s.except == nil {
+			s.except = make(map[string]bool)
+		}
+
+		for _, action := range actions {
+			s.except[action] = true
+		}
+	}
+}
+
+type actionSet struct {
+	only   map[string]bool
+	except map[string]bool
+}
+
+func newActionSet(opts ...ActionOption) *actionSet {
+	s := &actionSet{}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// includes reports whether the action (eg. "edit") is part of the set
+func (s *actionSet) includes(action string) bool {
+	if s.except[action] {
+		return false
+	}
+
+	if s.only != nil {
+		return s.only[action]
+	}
+
+	return true
+}
+
+// explicit reports whether the action was explicitly requested with Only
+func (s *actionSet) explicit(action string) bool {
+	return s.only[action]
+}
+
+// Resources registers the conventional RESTful routes for a registered controller:
+//
+//	GET    /contacts          contacts.index
+//	GET    /contacts/new      contacts.new
+//	POST   /contacts          contacts.create
+//	GET    /contacts/:id      contacts.show
+//	GET    /contacts/:id/edit contacts.edit
+//	PUT    /contacts/:id      contacts.update
+//	PATCH  /contacts/:id      contacts.update
+//	DELETE /contacts/:id      contacts.destroy
+//
+// Actions the controller does not implement are skipped, unless they were explicitly requested
+// with Only in which case Resources panics at startup.
+// Nested resources are registered by including the parent param in the path:
+// l.Resources("/contacts/:contact_id/notes", "notes")
+//
+// ctrl is the name of the controller (lowercased and without Controller suffix): "contacts"
+func (r *Router) Resources(path string, ctrl string, opts ...ActionOption) []*echo.Route {
+	set := newActionSet(opts...)
+
+	typeName := controllerTypeName(ctrl)
+
+	controller, exists := r.l.controllerRegistry[typeName]
+	if !exists {
+		panic(fmt.Sprintf("Controller type %s not found in registry", typeName))
+	}
+
+	path = strings.TrimSuffix(path, "/")
+
+	var routes []*echo.Route
+
+	for _, action := range resourceActions {
+		if !set.includes(action.name) {
+			continue
+		}
+
+		if _, found := controller.Type.MethodByName(actionMethodName(action.name)); !found {
+			if set.explicit(action.name) {
+				panic(fmt.Sprintf("Method %s not found on controller type %s", actionMethodName(action.name), typeName))
+			}
+
+			continue
+		}
+
+		routes = append(routes, r.add(action.method, path+action.suffix, ctrl+"."+action.name))
+	}
+
+	return routes
+}
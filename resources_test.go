@@ -0,0 +1,155 @@
+package loom
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+type notesController struct {
+	Controller
+}
+
+func (nc *notesController) Index(c echo.Context) error {
+	return c.String(http.StatusOK, "index "+c.Param("contact_id"))
+}
+
+func (nc *notesController) New(c echo.Context) error {
+	return c.String(http.StatusOK, "new")
+}
+
+func (nc *notesController) Create(c echo.Context) error {
+	return c.String(http.StatusCreated, "create")
+}
+
+func (nc *notesController) Show(c echo.Context) error {
+	return c.String(http.StatusOK, "show "+c.Param("id"))
+}
+
+func (nc *notesController) Update(c echo.Context) error {
+	return c.String(http.StatusOK, "update "+c.Param("id"))
+}
+
+func routeSet(routes []*echo.Route) map[string]bool {
+	set := make(map[string]bool)
+
+	for _, r := range routes {
+		set[r.Method+" "+r.Path] = true
+	}
+
+	return set
+}
+
+func TestResources(t *testing.T) {
+	loom := New(NewDeps())
+
+	Register[*notesController](loom)
+
+	routes := routeSet(loom.Resources("/notes", "notes"))
+
+	want := []string{
+		"GET /notes",
+		"GET /notes/new",
+		"POST /notes",
+		"GET /notes/:id",
+		"PUT /notes/:id",
+		"PATCH /notes/:id",
+	}
+
+	if len(routes) != len(want) {
+		t.Errorf("Resources() registered %d routes, want %d: %v", len(routes), len(want), routes)
+	}
+
+	for _, w := range want {
+		if !routes[w] {
+			t.Errorf("Resources() missing route %s", w)
+		}
+	}
+
+	// edit and destroy are not implemented and must be skipped
+	if routes["GET /notes/:id/edit"] || routes["DELETE /notes/:id"] {
+		t.Error("Resources() registered routes for unimplemented actions")
+	}
+}
+
+func TestResources_OnlyExcept(t *testing.T) {
+	loom := New(NewDeps())
+
+	Register[*notesController](loom)
+
+	only := routeSet(loom.Resources("/a", "notes", Only("index", "show")))
+	if len(only) != 2 || !only["GET /a"] || !only["GET /a/:id"] {
+		t.Errorf("Resources() with Only = %v", only)
+	}
+
+	except := routeSet(loom.Resources("/b", "notes", Except("update", "new")))
+	if len(except) != 3 || except["PUT /b/:id"] || except["GET /b/new"] {
+		t.Errorf("Resources() with Except = %v", except)
+	}
+}
+
+func TestResources_OnlyMissingAction(t *testing.T) {
+	loom := New(NewDeps())
+
+	Register[*notesController](loom)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Resources() expected panic for missing explicit action, got none")
+		}
+	}()
+
+	loom.Resources("/notes", "notes", Only("index", "destroy"))
+}
+
+func TestResources_ControllerNotFound(t *testing.T) {
+	loom := New(NewDeps())
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Resources() expected panic for non-existent controller, got none")
+		}
+	}()
+
+	loom.Resources("/notes", "notes")
+}
+
+func TestResources_Nested(t *testing.T) {
+	loom := New(NewDeps())
+
+	Register[*notesController](loom)
+
+	loom.Resources("/contacts/:contact_id/notes", "notes")
+
+	tests := []struct {
+		method string
+		path   string
+		code   int
+		body   string
+	}{
+		{http.MethodGet, "/contacts/7/notes", http.StatusOK, "index 7"},
+		{http.MethodGet, "/contacts/7/notes/new", http.StatusOK, "new"},
+		{http.MethodPost, "/contacts/7/notes", http.StatusCreated, "create"},
+		{http.MethodGet, "/contacts/7/notes/3", http.StatusOK, "show 3"},
+		{http.MethodPatch, "/contacts/7/notes/3", http.StatusOK, "update 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rec := httptest.NewRecorder()
+			loom.E.ServeHTTP(rec, req)
+
+			if rec.Code != tt.code {
+				t.Errorf("status = %v, want %v", rec.Code, tt.code)
+			}
+
+			if strings.TrimSpace(rec.Body.String()) != tt.body {
+				t.Errorf("body = %v, want %v", rec.Body.String(), tt.body)
+			}
+		})
+	}
+}
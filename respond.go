@@ -0,0 +1,235 @@
+package loom
+
+import (
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/labstack/echo/v4"
+)
+
+// Response formats negotiated by Respond
+const (
+	FormatHTML = "html"
+	FormatJSON = "json"
+)
+
+// formatKey is the echo context key holding the format requested with a URL extension
+const formatKey = "loom.format"
+
+// formatExtensions maps the URL extensions handled by FormatMiddleware to response formats
+var formatExtensions = map[string]string{
+	".html": FormatHTML,
+	".json": FormatJSON,
+}
+
+// formatMediaTypes maps Accept media types to response formats
+var formatMediaTypes = map[string]string{
+	echo.MIMETextHTML:        FormatHTML,
+	"application/xhtml+xml":  FormatHTML,
+	echo.MIMEApplicationJSON: FormatJSON,
+}
+
+// FormatMiddleware lets clients pick the response format with a URL extension:
+// GET /contacts/42.json is routed as GET /contacts/42 and answered with JSON by Respond.
+// The extension is only stripped when the path without it matches a route registered through
+// Loom and the path with it matches no other Loom route, so static files like /assets/manifest.json
+// and routes like GET /feed.json are served as is.
+// It has to run before routing: l.E.Pre(l.FormatMiddleware)
+func (l *Loom) FormatMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+
+		ext := path.Ext(req.URL.Path)
+
+		if format, ok := formatExtensions[ext]; ok {
+			stripped := strings.TrimSuffix(req.URL.Path, ext)
+
+			route, ok := l.matchRoute(req.Method, stripped)
+
+			// /contacts/42.json matches /contacts/:id too, with the extension in the param
+			if original, found := l.matchRoute(req.Method, req.URL.Path); ok && (!found || original == route) {
+				c.Set(formatKey, format)
+
+				req.URL.Path = stripped
+				req.URL.RawPath = strings.TrimSuffix(req.URL.RawPath, ext)
+			}
+		}
+
+		return next(c)
+	}
+}
+
+// matchRoute returns the path of the route registered through Loom a method and path request is routed to
+func (l *Loom) matchRoute(method, path string) (string, bool) {
+	c := l.E.NewContext(nil, nil)
+	l.E.Router().Find(method, path, c)
+
+	found := slices.ContainsFunc(l.routes, func(route RouteInfo) bool {
+		return route.Method == method && route.Path == c.Path()
+	})
+
+	return c.Path(), found
+}
+
+// Representation is one of the formats an action can respond with (see Respond)
+type Representation struct {
+	format string
+	write  func(c echo.Context, code int) error
+}
+
+// HTML responds with the component rendered by Loom.Renderer. HTMX requests
+// (except boosted ones) get the bare component without the layout.
+func HTML(component templ.Component, opts ...RenderOption) Representation {
+	return Representation{
+		format: FormatHTML,
+		write: func(c echo.Context, code int) error {
+			c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
+			c.Response().WriteHeader(code)
+
+			if isPartial(c) {
+				return component.Render(c.Request().Context(), c.Response().Writer)
+			}
+
+			return fromContext(c.Request().Context()).Renderer(c, component, opts...)
+		},
+	}
+}
+
+// JSON responds with the value encoded as JSON
+func JSON(value any) Representation {
+	return Representation{
+		format: FormatJSON,
+		write: func(c echo.Context, code int) error {
+			return c.JSON(code, value)
+		},
+	}
+}
+
+// Respond answers with the representation the client asked for, so one action
+// can serve browsers, HTMX and API clients. The format is picked from the URL extension
+// (see FormatMiddleware), then the Accept header, and defaults to the first representation.
+// It fails with 406 Not Acceptable if none of the representations is acceptable.
+// Usage: return loom.Respond(c, loom.HTML(contacts.Show(contact)), loom.JSON(contact))
+func Respond(c echo.Context, representations ...Representation) error {
+	return RespondWithStatus(c, http.StatusOK, representations...)
+}
+
+// RespondWithStatus is Respond with a status code other than 200 OK
+// Usage: return loom.RespondWithStatus(c, http.StatusUnprocessableEntity, loom.HTML(contacts.Form(m)), loom.JSON(m))
+func RespondWithStatus(c echo.Context, code int, representations ...Representation) error {
+	c.Response().Header().Add(echo.HeaderVary, "Accept, HX-Request")
+
+	offered := make([]string, len(representations))
+	for i, r := range representations {
+		offered[i] = r.format
+	}
+
+	format, ok := negotiate(c, offered)
+	if !ok {
+		return NewHTTPError(http.StatusNotAcceptable)
+	}
+
+	for _, r := range representations {
+		if r.format == format {
+			return r.write(c, code)
+		}
+	}
+
+	return NewHTTPError(http.StatusNotAcceptable)
+}
+
+// isPartial reports whether the request was made by HTMX to swap a part of the page
+func isPartial(c echo.Context) bool {
+	header := c.Request().Header
+
+	return header.Get("HX-Request") == "true" && header.Get("HX-Boosted") != "true"
+}
+
+// negotiate picks the format of the response out of the offered ones
+func negotiate(c echo.Context, offered []string) (string, bool) {
+	if len(offered) == 0 {
+		return "", false
+	}
+
+	if format, ok := c.Get(formatKey).(string); ok {
+		return format, slices.Contains(offered, format)
+	}
+
+	if isPartial(c) && slices.Contains(offered, FormatHTML) {
+		return FormatHTML, true
+	}
+
+	accept := c.Request().Header.Get(echo.HeaderAccept)
+	if accept == "" {
+		return offered[0], true
+	}
+
+	for _, mediaRange := range parseAccept(accept) {
+		for _, format := range offered {
+			if mediaRange.matches(format) {
+				return format, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// requestedFormat returns the format the client prefers, without anything being offered
+func requestedFormat(c echo.Context) string {
+	format, _ := negotiate(c, []string{FormatHTML, FormatJSON})
+	return format
+}
+
+type mediaRange struct {
+	mediaType string
+	q         float64
+}
+
+func (m mediaRange) matches(format string) bool {
+	switch m.mediaType {
+	case "*/*":
+		return true
+	case "text/*":
+		return format == FormatHTML
+	case "application/*":
+		return format == FormatJSON
+	}
+
+	return formatMediaTypes[m.mediaType] == format
+}
+
+// parseAccept parses the Accept header into media ranges ordered by preference
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		if q <= 0 {
+			continue
+		}
+
+		ranges = append(ranges, mediaRange{mediaType: mediaType, q: q})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	return ranges
+}
@@ -0,0 +1,157 @@
+package loom
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/a-h/templ"
+	"github.com/labstack/echo/v4"
+)
+
+type item struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type itemsController struct {
+	Controller
+}
+
+func (ic *itemsController) Show(c echo.Context) error {
+	it := item{ID: c.Param("id"), Name: "pencil"}
+
+	return Respond(c, HTML(templ.Raw("<p>"+it.Name+"</p>")), JSON(it))
+}
+
+func (ic *itemsController) Index(c echo.Context) error {
+	return Respond(c, JSON([]item{}))
+}
+
+func (ic *itemsController) Create(c echo.Context) error {
+	m := ViewModel{Errors: map[string]string{"name": "name is required"}}
+
+	return RespondWithStatus(c, http.StatusUnprocessableEntity, HTML(templ.Raw("form")), JSON(m))
+}
+
+func newItemsApp() *Loom {
+	l := New(NewDeps())
+
+	l.Renderer = func(c echo.Context, component templ.Component, _ ...RenderOption) error {
+		return testLayout("layout")(component).Render(c.Request().Context(), c.Response().Writer)
+	}
+
+	l.E.Pre(l.FormatMiddleware)
+
+	Register[*itemsController](l)
+
+	l.GET("/items", "items.index")
+	l.POST("/items", "items.create")
+	l.GET("/items/:id", "items.show")
+
+	return l
+}
+
+func TestRespond(t *testing.T) {
+	l := newItemsApp()
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		headers map[string]string
+		code    int
+		body    string
+	}{
+		{"defaults to first", http.MethodGet, "/items/1", nil, http.StatusOK, "layout:<p>pencil</p>"},
+		{"accept html", http.MethodGet, "/items/1", map[string]string{"Accept": "text/html,application/xhtml+xml,*/*;q=0.8"}, http.StatusOK, "layout:<p>pencil</p>"},
+		{"accept json", http.MethodGet, "/items/1", map[string]string{"Accept": "application/json"}, http.StatusOK, `{"id":"1","name":"pencil"}`},
+		{"accept quality", http.MethodGet, "/items/1", map[string]string{"Accept": "text/html;q=0.5, application/json"}, http.StatusOK, `{"id":"1","name":"pencil"}`},
+		{"json extension", http.MethodGet, "/items/1.json", map[string]string{"Accept": "text/html"}, http.StatusOK, `{"id":"1","name":"pencil"}`},
+		{"html extension", http.MethodGet, "/items/1.html", nil, http.StatusOK, "layout:<p>pencil</p>"},
+		{"htmx partial", http.MethodGet, "/items/1", map[string]string{"HX-Request": "true"}, http.StatusOK, "<p>pencil</p>"},
+		{"htmx boosted", http.MethodGet, "/items/1", map[string]string{"HX-Request": "true", "HX-Boosted": "true"}, http.StatusOK, "layout:<p>pencil</p>"},
+		{"not acceptable", http.MethodGet, "/items", map[string]string{"Accept": "text/html"}, http.StatusNotAcceptable, `{"message":"Not Acceptable"}`},
+		{"unsupported extension", http.MethodGet, "/items.html", nil, http.StatusNotAcceptable, `{"message":"Not Acceptable"}`},
+		{"with status", http.MethodPost, "/items.json", nil, http.StatusUnprocessableEntity, `{"message":"Validation failed","errors":{"name":"name is required"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			rec := httptest.NewRecorder()
+			l.E.ServeHTTP(rec, req)
+
+			if rec.Code != tt.code {
+				t.Errorf("status = %v, want %v", rec.Code, tt.code)
+			}
+
+			if body := strings.TrimSpace(rec.Body.String()); body != tt.body {
+				t.Errorf("body = %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
+
+func TestRespond_ErrorsAsJSON(t *testing.T) {
+	l := newFailingApp(true)
+	l.E.Pre(l.FormatMiddleware)
+
+	rec := serve(l, http.MethodGet, "/forbidden.json")
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %v, want %v", rec.Code, http.StatusForbidden)
+	}
+
+	if body := strings.TrimSpace(rec.Body.String()); body != `{"message":"You can't see this"}` {
+		t.Errorf("body = %q", body)
+	}
+}
+
+func TestFormatMiddleware_StaticFiles(t *testing.T) {
+	l := newItemsApp()
+
+	l.E.StaticFS("/assets", fstest.MapFS{
+		"manifest.json": {Data: []byte(`{"name":"items"}`)},
+	})
+
+	l.GET("/items/feed.json", "items.index")
+
+	tests := []struct {
+		path string
+		body string
+	}{
+		{"/assets/manifest.json", `{"name":"items"}`},
+		{"/items/feed.json", "[]"},
+	}
+
+	for _, tt := range tests {
+		rec := serve(l, http.MethodGet, tt.path)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s status = %v, want %v", tt.path, rec.Code, http.StatusOK)
+		}
+
+		if body := strings.TrimSpace(rec.Body.String()); body != tt.body {
+			t.Errorf("%s body = %q, want %q", tt.path, body, tt.body)
+		}
+	}
+}
+
+func TestViewModel_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(ViewModel{Values: map[string]string{"name": ""}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `{"errors":{}}` {
+		t.Errorf("json.Marshal(ViewModel) = %s", data)
+	}
+}
@@ -0,0 +1,165 @@
+package loom
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/a-h/templ"
+	"github.com/labstack/echo/v4"
+)
+
+// echoRouter is implemented by both *echo.Echo and *echo.Group
+type echoRouter interface {
+	Add(method, path string, handler echo.HandlerFunc, middleware ...echo.MiddlewareFunc) *echo.Route
+	Group(prefix string, m ...echo.MiddlewareFunc) *echo.Group
+}
+
+// Router registers routes whose handlers are controller actions ("controller.action").
+// Loom itself is the root router, and Group creates nested routers sharing a path prefix,
+// middleware and a default layout.
+type Router struct {
+	l      *Loom
+	echo   echoRouter
+	layout func(component templ.Component) templ.Component
+
+	// middleware holds the names of the group-level middleware for the route table
+	middleware []string
+}
+
+// Group creates a new router group with prefix and optional group-level middleware.
+// Groups can be nested and inherit the layout of the group they were created from.
+// Usage: admin := l.Group("/admin", authMiddleware)
+func (r *Router) Group(prefix string, m ...echo.MiddlewareFunc) *Router {
+	return &Router{
+		l:          r.l,
+		echo:       r.echo.Group(prefix, m...),
+		layout:     r.layout,
+		middleware: append(slices.Clone(r.middleware), funcNames(m)...),
+	}
+}
+
+// Layout sets the default layout for the routes registered on this router after the call,
+// and for groups created from it. Render helpers pick it up with LayoutFrom.
+// Usage: l.Group("/admin").Layout(layouts.Admin)
+func (r *Router) Layout(layout func(component templ.Component) templ.Component) *Router {
+	r.layout = layout
+
+	return r
+}
+
+// GET registers a new GET route for a path with a matching handler in the router
+// with optional route-level middleware.
+//
+// ctrlAction is the name of the controller action pair to be called (lowercased and without Controller suffix):
+// "users.index"
+func (r *Router) GET(path string, ctrlAction string, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodGet, path, ctrlAction, m...)
+}
+
+// POST registers a new POST route for a path with a matching handler in the router
+// with optional route-level middleware.
+//
+// ctrlAction is the name of the controller action pair to be called (lowercased and without Controller suffix):
+// "users.index"
+func (r *Router) POST(path string, ctrlAction string, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodPost, path, ctrlAction, m...)
+}
+
+// PUT registers a new PUT route for a path with a matching handler in the router
+// with optional route-level middleware.
+//
+// ctrlAction is the name of the controller action pair to be called (lowercased and without Controller suffix):
+// "users.index"
+func (r *Router) PUT(path string, ctrlAction string, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodPut, path, ctrlAction, m...)
+}
+
+// PATCH registers a new PATCH route for a path with a matching handler in the router
+// with optional route-level middleware.
+//
+// ctrlAction is the name of the controller action pair to be called (lowercased and without Controller suffix):
+// "users.index"
+func (r *Router) PATCH(path string, ctrlAction string, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodPatch, path, ctrlAction, m...)
+}
+
+// DELETE registers a new DELETE route for a path with a matching handler in the router
+// with optional route-level middleware.
+//
+// ctrlAction is the name of the controller action pair to be called (lowercased and without Controller suffix):
+// "users.index"
+func (r *Router) DELETE(path string, ctrlAction string, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodDelete, path, ctrlAction, m...)
+}
+
+// HEAD registers a new HEAD route for a path with a matching handler in the router
+// with optional route-level middleware.
+//
+// ctrlAction is the name of the controller action pair to be called (lowercased and without Controller suffix):
+// "users.index"
+func (r *Router) HEAD(path string, ctrlAction string, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodHead, path, ctrlAction, m...)
+}
+
+// OPTIONS registers a new OPTIONS route for a path with a matching handler in the router
+// with optional route-level middleware.
+//
+// ctrlAction is the name of the controller action pair to be called (lowercased and without Controller suffix):
+// "users.index"
+func (r *Router) OPTIONS(path string, ctrlAction string, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodOptions, path, ctrlAction, m...)
+}
+
+// Any registers a new route for all supported HTTP methods for a path with a matching handler
+// in the router with optional route-level middleware.
+//
+// ctrlAction is the name of the controller action pair to be called (lowercased and without Controller suffix):
+// "users.index"
+func (r *Router) Any(path string, ctrlAction string, m ...echo.MiddlewareFunc) []*echo.Route {
+	handler := r.l.handlerFor(ctrlAction)
+	chain := r.chain(m)
+
+	routes := make([]*echo.Route, len(anyMethods))
+	for i, method := range anyMethods {
+		routes[i] = r.l.named(r.echo.Add(method, path, handler, r.withLayout(m)...), ctrlAction, chain)
+	}
+
+	return routes
+}
+
+// anyMethods are the methods registered by Any, mirroring echo's Any
+var anyMethods = []string{
+	http.MethodConnect,
+	http.MethodDelete,
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodPatch,
+	http.MethodPost,
+	echo.PROPFIND,
+	http.MethodPut,
+	http.MethodTrace,
+	echo.REPORT,
+}
+
+func (r *Router) add(method, path, ctrlAction string, m ...echo.MiddlewareFunc) *echo.Route {
+	route := r.echo.Add(method, path, r.l.handlerFor(ctrlAction), r.withLayout(m)...)
+
+	return r.l.named(route, ctrlAction, r.chain(m))
+}
+
+// chain returns the names of the group and route middleware a route registered with m runs through
+func (r *Router) chain(m []echo.MiddlewareFunc) []string {
+	chain := make([]string, 0, len(r.middleware)+len(m))
+
+	return append(append(chain, r.middleware...), funcNames(m)...)
+}
+
+// withLayout prepends the layout middleware of the group to the route middleware m
+func (r *Router) withLayout(m []echo.MiddlewareFunc) []echo.MiddlewareFunc {
+	if r.layout == nil {
+		return m
+	}
+
+	return append([]echo.MiddlewareFunc{layoutMiddleware(r.layout)}, m...)
+}
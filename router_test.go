@@ -0,0 +1,130 @@
+package loom
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/labstack/echo/v4"
+)
+
+type layoutController struct {
+	Controller
+}
+
+func (lc *layoutController) Index(c echo.Context) error {
+	layout, ok := LayoutFrom(c.Request().Context())
+	if !ok {
+		return c.String(http.StatusOK, "no layout")
+	}
+
+	return layout(templ.Raw("content")).Render(c.Request().Context(), c.Response().Writer)
+}
+
+func testLayout(name string) func(component templ.Component) templ.Component {
+	return func(component templ.Component) templ.Component {
+		return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			if _, err := io.WriteString(w, name+":"); err != nil {
+				return err
+			}
+
+			return component.Render(ctx, w)
+		})
+	}
+}
+
+func headerMiddleware(value string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Response().Header().Add("X-Group", value)
+			return next(c)
+		}
+	}
+}
+
+func serve(l *Loom, method, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	rec := httptest.NewRecorder()
+	l.E.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestRouter_Group(t *testing.T) {
+	loom := New(NewDeps())
+
+	Register[*testController](loom)
+
+	admin := loom.Group("/admin", headerMiddleware("admin"))
+	admin.GET("/users", "test.index")
+
+	reports := admin.Group("/reports", headerMiddleware("reports"))
+	reports.GET("/:id", "test.show")
+
+	rec := serve(loom, http.MethodGet, "/admin/users")
+	if strings.TrimSpace(rec.Body.String()) != "index" {
+		t.Errorf("body = %v, want %v", rec.Body.String(), "index")
+	}
+	if got := rec.Header().Values("X-Group"); len(got) != 1 || got[0] != "admin" {
+		t.Errorf("X-Group = %v, want [admin]", got)
+	}
+
+	rec = serve(loom, http.MethodGet, "/admin/reports/1")
+	if strings.TrimSpace(rec.Body.String()) != "show" {
+		t.Errorf("body = %v, want %v", rec.Body.String(), "show")
+	}
+	if got := rec.Header().Values("X-Group"); len(got) != 2 || got[0] != "admin" || got[1] != "reports" {
+		t.Errorf("X-Group = %v, want [admin reports]", got)
+	}
+}
+
+func TestRouter_GroupResources(t *testing.T) {
+	loom := New(NewDeps())
+
+	Register[*notesController](loom)
+
+	routes := routeSet(loom.Group("/api").Resources("/notes", "notes", Only("show")))
+
+	if !routes["GET /api/notes/:id"] {
+		t.Errorf("Resources() on group = %v, want GET /api/notes/:id", routes)
+	}
+}
+
+func TestRouter_Layout(t *testing.T) {
+	loom := New(NewDeps())
+
+	Register[*layoutController](loom)
+
+	loom.GET("/", "layout.index")
+
+	admin := loom.Group("/admin").Layout(testLayout("admin"))
+	admin.GET("", "layout.index")
+
+	// nested groups inherit the layout unless they set their own
+	admin.Group("/inherited").GET("", "layout.index")
+	admin.Group("/reports").Layout(testLayout("reports")).GET("", "layout.index")
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/", "no layout"},
+		{"/admin", "admin:content"},
+		{"/admin/inherited", "admin:content"},
+		{"/admin/reports", "reports:content"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := serve(loom, http.MethodGet, tt.path)
+
+			if strings.TrimSpace(rec.Body.String()) != tt.want {
+				t.Errorf("body = %v, want %v", rec.Body.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package loom
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+type scopedController struct {
+	Controller
+
+	Service     *testService `loom:"inject"`
+	currentUser string
+}
+
+func (sc *scopedController) BeforeAction(c echo.Context) error {
+	sc.currentUser = c.QueryParam("user")
+	return nil
+}
+
+func (sc *scopedController) Show(c echo.Context) error {
+	return c.String(http.StatusOK, sc.Service.Name+":"+sc.currentUser)
+}
+
+func newScopedLoom() *Loom {
+	deps := NewDeps()
+	Add(deps, &testService{Name: "shared"})
+
+	loom := New(deps)
+
+	RegisterScoped[*scopedController](loom)
+
+	loom.GET("/me", "scoped.show")
+
+	return loom
+}
+
+// TestRegisterScoped_Concurrent is meant to be run with -race, a shared controller
+// instance would race on currentUser
+func TestRegisterScoped_Concurrent(t *testing.T) {
+	loom := newScopedLoom()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+
+		go func(user string) {
+			defer wg.Done()
+
+			rec := serve(loom, http.MethodGet, "/me?user="+user)
+
+			if want := "shared:" + user; rec.Body.String() != want {
+				t.Errorf("body = %v, want %v", rec.Body.String(), want)
+			}
+		}(fmt.Sprintf("user%d", i))
+	}
+
+	wg.Wait()
+
+	template := loom.controllerRegistry["ScopedController"].Instance.(*scopedController)
+	if template.currentUser != "" {
+		t.Errorf("template instance was modified: currentUser = %v", template.currentUser)
+	}
+}
+
+func BenchmarkHandler_Singleton(b *testing.B) {
+	deps := NewDeps()
+	Add(deps, &testService{Name: "shared"})
+
+	loom := New(deps)
+
+	Register[*scopedController](loom)
+
+	benchmarkRequests(b, loom)
+}
+
+func BenchmarkHandler_Scoped(b *testing.B) {
+	benchmarkRequests(b, newScopedLoom())
+}
+
+func benchmarkRequests(b *testing.B, loom *Loom) {
+	handler := loom.handlerFor("scoped.show")
+
+	req := httptest.NewRequest(http.MethodGet, "/me?user=bench", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c := loom.E.NewContext(req, httptest.NewRecorder())
+
+		if err := handler(c); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
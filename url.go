@@ -0,0 +1,125 @@
+package loom
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type loomKey struct{}
+
+// contextMiddleware makes the Loom instance available to URL and other
+// context based helpers used from templ components, and closes the request scope (see Resolve)
+func (l *Loom) contextMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := context.WithValue(c.Request().Context(), loomKey{}, l)
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		err := next(c)
+
+		// close the scoped dependencies resolved during the request
+		if s, ok := c.Get(scopeKey).(*scope); ok {
+			if closeErr := s.close(); closeErr != nil {
+				c.Logger().Error(closeErr)
+			}
+		}
+
+		return err
+	}
+}
+
+func fromContext(ctx context.Context) *Loom {
+	l, ok := ctx.Value(loomKey{}).(*Loom)
+	if !ok {
+		panic("loom: no Loom instance in context, was the request served by Loom?")
+	}
+
+	return l
+}
+
+// named names the route after its ctrlAction and records it in the route table
+// along with the names of the middleware it runs through
+func (l *Loom) named(route *echo.Route, ctrlAction string, middleware []string) *echo.Route {
+	route.Name = ctrlAction
+
+	parts := strings.Split(ctrlAction, ".")
+	typeName := controllerTypeName(parts[0])
+
+	l.routes = append(l.routes, RouteInfo{
+		Method:     route.Method,
+		Path:       route.Path,
+		Name:       ctrlAction,
+		Controller: l.controllerRegistry[typeName].Type.String(),
+		Action:     actionMethodName(parts[1]),
+		Middleware: middleware,
+	})
+
+	return route
+}
+
+// URL builds the path of a named route, filling in the path params in order.
+// Routes are named after their ctrlAction, when several routes share a name the first
+// one registered is used. It panics if the route does not exist
+// or the number of params does not match.
+// Usage: l.URL("contacts.show", 42) // /contacts/42
+func (l *Loom) URL(name string, params ...any) string {
+	var path string
+
+	for _, route := range l.routes {
+		if route.Name == name {
+			path = route.Path
+			break
+		}
+	}
+
+	if path == "" {
+		panic(fmt.Sprintf("Route %s not found", name))
+	}
+
+	if want := len(PathParams(path)); want != len(params) {
+		panic(fmt.Sprintf("Route %s (%s) expects %d params, got %d", name, path, want, len(params)))
+	}
+
+	// the params are filled in the path found above, echo may reverse the name to
+	// another route sharing it, eg. contacts.show under /contacts and /admin/contacts
+	segments := strings.Split(path, "/")
+	n := 0
+
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = url.PathEscape(fmt.Sprint(params[n]))
+			n++
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// URL builds the path of a named route from within a request, a templ component or a controller:
+//
+//	loom.URL(ctx, "contacts.show", contact.ID)
+//	loom.URL(c.Request().Context(), "contacts.index")
+func URL(ctx context.Context, name string, params ...any) string {
+	return fromContext(ctx).URL(name, params...)
+}
+
+// PathParams returns the names of the params in a route path in order,
+// the wildcard param is named "*":
+// "/contacts/:contact_id/notes/:id" -> ["contact_id", "id"]
+func PathParams(path string) []string {
+	var params []string
+
+	for _, segment := range strings.Split(path, "/") {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			params = append(params, segment[1:])
+		case strings.HasPrefix(segment, "*"):
+			params = append(params, "*")
+		}
+	}
+
+	return params
+}
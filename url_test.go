@@ -0,0 +1,150 @@
+package loom
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+type linksController struct {
+	Controller
+}
+
+func (lc *linksController) Show(c echo.Context) error {
+	return c.String(http.StatusOK, URL(c.Request().Context(), "notes.show", c.Param("contact_id"), "1"))
+}
+
+func TestLoom_URL(t *testing.T) {
+	loom := New(NewDeps())
+
+	Register[*notesController](loom)
+	Register[*testController](loom)
+
+	loom.Resources("/contacts/:contact_id/notes", "notes")
+	loom.Group("/admin").GET("/files/*", "test.show")
+
+	// a later route sharing the name with a different path
+	loom.GET("/admin/notes/:id", "notes.show")
+
+	tests := []struct {
+		name   string
+		params []any
+		want   string
+	}{
+		{"notes.index", []any{7}, "/contacts/7/notes"},
+		{"notes.new", []any{7}, "/contacts/7/notes/new"},
+		{"notes.show", []any{7, 3}, "/contacts/7/notes/3"},
+		{"notes.update", []any{"a b", 3}, "/contacts/a%20b/notes/3"},
+		{"test.show", []any{"report.pdf"}, "/admin/files/report.pdf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := loom.URL(tt.name, tt.params...); got != tt.want {
+				t.Errorf("URL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoom_URL_Panics(t *testing.T) {
+	loom := New(NewDeps())
+
+	Register[*notesController](loom)
+
+	loom.Resources("/notes", "notes")
+
+	tests := []struct {
+		name   string
+		params []any
+	}{
+		{"notes.missing", nil},
+		{"notes.show", nil},
+		{"notes.index", []any{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("URL() expected panic, got none")
+				}
+			}()
+
+			loom.URL(tt.name, tt.params...)
+		})
+	}
+}
+
+func TestURL_FromContext(t *testing.T) {
+	loom := New(NewDeps())
+
+	Register[*notesController](loom)
+	Register[*linksController](loom)
+
+	loom.Resources("/contacts/:contact_id/notes", "notes", Only("show"))
+	loom.GET("/links/:contact_id", "links.show")
+
+	rec := serve(loom, http.MethodGet, "/links/9")
+
+	if strings.TrimSpace(rec.Body.String()) != "/contacts/9/notes/1" {
+		t.Errorf("body = %v, want %v", rec.Body.String(), "/contacts/9/notes/1")
+	}
+}
+
+func TestPathParams(t *testing.T) {
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/", nil},
+		{"/contacts/:id", []string{"id"}},
+		{"/contacts/:contact_id/notes/:id/edit", []string{"contact_id", "id"}},
+		{"/files/*", []string{"*"}},
+	}
+
+	for _, tt := range tests {
+		if got := PathParams(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("PathParams(%s) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLoom_Start_Inspect(t *testing.T) {
+	deps := NewDeps()
+
+	// the CLI inspects apps without running their factories
+	Provide(deps, func(d *Deps) (*repo, error) {
+		t.Error("factory built while inspecting")
+		return nil, errors.New("no database")
+	})
+
+	loom := New(deps)
+	loom.ValidateDeps = true
+
+	Register[*testController](loom)
+
+	loom.GET("/test", "test.index")
+
+	report := filepath.Join(t.TempDir(), "report.json")
+	t.Setenv(InspectEnv, report)
+
+	if err := loom.Start(":0"); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	data, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatalf("failed to read report: %v", err)
+	}
+
+	if !strings.Contains(string(data), `"name": "test.index"`) {
+		t.Errorf("report = %s, want it to contain test.index", data)
+	}
+}